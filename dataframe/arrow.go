@@ -0,0 +1,527 @@
+package dataframe
+
+import (
+	"fmt"
+	"io"
+	"koalas/series"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// arrowType returns the Arrow type used to store a Series datatype
+func arrowType(datatype string) (arrow.DataType, error) {
+	if precision, scale, ok := series.ParseDecimalType(datatype); ok {
+		return &arrow.Decimal128Type{Precision: int32(precision), Scale: int32(scale)}, nil
+	}
+	switch datatype {
+	case "category":
+		return &arrow.DictionaryType{IndexType: arrow.PrimitiveTypes.Int32, ValueType: arrow.BinaryTypes.String}, nil
+	case "int":
+		return arrow.PrimitiveTypes.Int64, nil
+	case "float":
+		return arrow.PrimitiveTypes.Float64, nil
+	case "string":
+		return arrow.BinaryTypes.String, nil
+	case "bool":
+		return arrow.FixedWidthTypes.Boolean, nil
+	case "int8":
+		return arrow.PrimitiveTypes.Int8, nil
+	case "int16":
+		return arrow.PrimitiveTypes.Int16, nil
+	case "int32":
+		return arrow.PrimitiveTypes.Int32, nil
+	case "int64":
+		return arrow.PrimitiveTypes.Int64, nil
+	case "uint8":
+		return arrow.PrimitiveTypes.Uint8, nil
+	case "uint16":
+		return arrow.PrimitiveTypes.Uint16, nil
+	case "uint32":
+		return arrow.PrimitiveTypes.Uint32, nil
+	case "uint64":
+		return arrow.PrimitiveTypes.Uint64, nil
+	case "float32":
+		return arrow.PrimitiveTypes.Float32, nil
+	case "datetime":
+		return &arrow.TimestampType{Unit: arrow.Nanosecond, TimeZone: "UTC"}, nil
+	case "duration":
+		return arrow.FixedWidthTypes.Duration_ns, nil
+	}
+	return nil, fmt.Errorf("unsupported type for arrow: %s", datatype)
+}
+
+// arrowDatatypeKey marks the fields whose datatype cannot be told from their
+// Arrow type alone. Both "int" and "int64" are written as INT64, so "int64"
+// columns carry the key to be read back as "int64" rather than "int".
+const arrowDatatypeKey = "koalas.datatype"
+
+// koalasType returns the Series datatype that holds values of an Arrow type.
+// Sized integers and FLOAT32 keep their width, INT64 is read as "int" and
+// FLOAT64 as "float", and FLOAT16 widens to "float32". Decimals keep their
+// precision and scale, and dictionaries of strings are read as "category".
+// Timestamps of any unit are read as "datetime" in UTC and durations as
+// "duration".
+func koalasType(dt arrow.DataType) (string, error) {
+	switch dt.ID() {
+	case arrow.DICTIONARY:
+		switch dt.(*arrow.DictionaryType).ValueType.ID() {
+		case arrow.STRING, arrow.LARGE_STRING:
+			return "category", nil
+		}
+	case arrow.DECIMAL128:
+		d := dt.(*arrow.Decimal128Type)
+		return series.DecimalType(int(d.Precision), int(d.Scale)), nil
+	case arrow.INT8:
+		return "int8", nil
+	case arrow.INT16:
+		return "int16", nil
+	case arrow.INT32:
+		return "int32", nil
+	case arrow.INT64:
+		return "int", nil
+	case arrow.UINT8:
+		return "uint8", nil
+	case arrow.UINT16:
+		return "uint16", nil
+	case arrow.UINT32:
+		return "uint32", nil
+	case arrow.UINT64:
+		return "uint64", nil
+	case arrow.FLOAT16, arrow.FLOAT32:
+		return "float32", nil
+	case arrow.FLOAT64:
+		return "float", nil
+	case arrow.STRING, arrow.LARGE_STRING, arrow.STRING_VIEW:
+		return "string", nil
+	case arrow.BOOL:
+		return "bool", nil
+	case arrow.TIMESTAMP:
+		return "datetime", nil
+	case arrow.DURATION:
+		return "duration", nil
+	}
+	return "", fmt.Errorf("unsupported arrow type: %s", dt)
+}
+
+// arrowSchema builds the Arrow schema of the DataFrame, every field is nullable
+func (df *DataFrame) arrowSchema() (*arrow.Schema, error) {
+	fields := make([]arrow.Field, 0, df.numCols)
+	for _, name := range df.columns.Keys() {
+		dt, err := arrowType(df.schema[name])
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %v", name, err)
+		}
+		field := arrow.Field{Name: name, Type: dt, Nullable: true}
+		if df.schema[name] == "int64" {
+			field.Metadata = arrow.NewMetadata([]string{arrowDatatypeKey}, []string{"int64"})
+		}
+		fields = append(fields, field)
+	}
+	return arrow.NewSchema(fields, nil), nil
+}
+
+// arrowRecord copies the DataFrame into a single Arrow record batch,
+// turning nil values into nulls in the validity bitmap
+func (df *DataFrame) arrowRecord(mem memory.Allocator) (arrow.RecordBatch, error) {
+	schema, err := df.arrowSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	builder := array.NewRecordBuilder(mem, schema)
+	defer builder.Release()
+
+	for i, name := range df.columns.Keys() {
+		col, _ := df.columns.Get(name)
+		fb := builder.Field(i)
+
+		// Seed the dictionary of a category column so that it keeps its order
+		if db, ok := fb.(*array.BinaryDictionaryBuilder); ok {
+			labels, err := col.Cat().Categories()
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %v", name, err)
+			}
+			sb := array.NewStringBuilder(mem)
+			sb.AppendValues(labels, nil)
+			dict := sb.NewStringArray()
+			err = db.InsertStringDictValues(dict)
+			dict.Release()
+			sb.Release()
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %v", name, err)
+			}
+		}
+
+		for _, entry := range col.Data {
+			if entry.Value == nil {
+				fb.AppendNull()
+				continue
+			}
+			if err := appendArrowValue(fb, entry.Value); err != nil {
+				return nil, fmt.Errorf("column %s: %v", name, err)
+			}
+		}
+	}
+
+	return builder.NewRecordBatch(), nil
+}
+
+// appendArrowValue appends one non-nil value to an Arrow array builder
+func appendArrowValue(b array.Builder, value interface{}) error {
+	switch b := b.(type) {
+	case *array.Int64Builder:
+		switch v := value.(type) {
+		case int:
+			b.Append(int64(v))
+			return nil
+		case int64:
+			b.Append(v)
+			return nil
+		}
+	case *array.Int8Builder:
+		if v, ok := value.(int8); ok {
+			b.Append(v)
+			return nil
+		}
+	case *array.Int16Builder:
+		if v, ok := value.(int16); ok {
+			b.Append(v)
+			return nil
+		}
+	case *array.Int32Builder:
+		if v, ok := value.(int32); ok {
+			b.Append(v)
+			return nil
+		}
+	case *array.Uint8Builder:
+		if v, ok := value.(uint8); ok {
+			b.Append(v)
+			return nil
+		}
+	case *array.Uint16Builder:
+		if v, ok := value.(uint16); ok {
+			b.Append(v)
+			return nil
+		}
+	case *array.Uint32Builder:
+		if v, ok := value.(uint32); ok {
+			b.Append(v)
+			return nil
+		}
+	case *array.Uint64Builder:
+		if v, ok := value.(uint64); ok {
+			b.Append(v)
+			return nil
+		}
+	case *array.Float32Builder:
+		if v, ok := value.(float32); ok {
+			b.Append(v)
+			return nil
+		}
+	case *array.Float64Builder:
+		if v, ok := value.(float64); ok {
+			b.Append(v)
+			return nil
+		}
+	case *array.StringBuilder:
+		if v, ok := value.(string); ok {
+			b.Append(v)
+			return nil
+		}
+	case *array.BooleanBuilder:
+		if v, ok := value.(bool); ok {
+			b.Append(v)
+			return nil
+		}
+	case *array.BinaryDictionaryBuilder:
+		if v, ok := value.(series.Category); ok {
+			return b.AppendString(v.Label())
+		}
+	case *array.Decimal128Builder:
+		if v, ok := value.(series.Decimal); ok {
+			b.Append(decimal128.FromBigInt(v.Coefficient()))
+			return nil
+		}
+	case *array.TimestampBuilder:
+		if v, ok := value.(time.Time); ok {
+			n, err := unixNanos(v)
+			if err != nil {
+				return err
+			}
+			b.Append(arrow.Timestamp(n))
+			return nil
+		}
+	case *array.DurationBuilder:
+		if v, ok := value.(time.Duration); ok {
+			b.Append(arrow.Duration(v))
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid type: cannot store %T in %s", value, b.Type())
+}
+
+// arrowValue returns the value at position i of an Arrow array as a Series value
+func arrowValue(arr arrow.Array, i int) interface{} {
+	if arr.IsNull(i) {
+		return nil
+	}
+	switch a := arr.(type) {
+	case *array.Int8:
+		return a.Value(i)
+	case *array.Int16:
+		return a.Value(i)
+	case *array.Int32:
+		return a.Value(i)
+	case *array.Int64:
+		return int(a.Value(i))
+	case *array.Uint8:
+		return a.Value(i)
+	case *array.Uint16:
+		return a.Value(i)
+	case *array.Uint32:
+		return a.Value(i)
+	case *array.Uint64:
+		return a.Value(i)
+	case *array.Float16:
+		return a.Value(i).Float32()
+	case *array.Float32:
+		return a.Value(i)
+	case *array.Float64:
+		return a.Value(i)
+	case *array.String:
+		return a.Value(i)
+	case *array.LargeString:
+		return a.Value(i)
+	case *array.StringView:
+		return a.Value(i)
+	case *array.Boolean:
+		return a.Value(i)
+	case *array.Dictionary:
+		return a.Dictionary().ValueStr(a.GetValueIndex(i))
+	case *array.Decimal128:
+		scale := a.DataType().(*arrow.Decimal128Type).Scale
+		d, _ := series.NewDecimalFromBig(a.Value(i).BigInt(), int(scale))
+		return d
+	case *array.Timestamp:
+		return a.Value(i).ToTime(a.DataType().(*arrow.TimestampType).Unit)
+	case *array.Duration:
+		return time.Duration(a.Value(i)) * a.DataType().(*arrow.DurationType).Unit.Multiplier()
+	}
+	return nil
+}
+
+// unixNanos returns a time as nanoseconds since the Unix epoch, failing for
+// times that do not fit in an int64, outside the years 1678 to 2262
+func unixNanos(t time.Time) (int64, error) {
+	n := t.UnixNano()
+	if !time.Unix(0, n).Equal(t) {
+		return 0, fmt.Errorf("datetime %s is out of range for nanosecond timestamps", t.Format(time.RFC3339Nano))
+	}
+	return n, nil
+}
+
+// fromArrowRecords copies a list of record batches sharing one schema into a new DataFrame
+func fromArrowRecords(schema *arrow.Schema, records []arrow.RecordBatch) (*DataFrame, error) {
+	tbl := array.NewTableFromRecords(schema, records)
+	defer tbl.Release()
+	return fromArrowTable(tbl)
+}
+
+// fromArrowTable copies every chunk of an Arrow table into a new DataFrame
+func fromArrowTable(tbl arrow.Table) (*DataFrame, error) {
+	schema := tbl.Schema()
+	seriesList := make([]*series.Series, schema.NumFields())
+	for i, field := range schema.Fields() {
+		datatype, err := koalasType(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %v", field.Name, err)
+		}
+
+		values := make([]interface{}, 0, tbl.NumRows())
+		for _, chunk := range tbl.Column(i).Data().Chunks() {
+			for j := 0; j < chunk.Len(); j++ {
+				values = append(values, arrowValue(chunk, j))
+			}
+		}
+		if key := field.Metadata.FindKey(arrowDatatypeKey); datatype == "int" && key >= 0 && field.Metadata.Values()[key] == "int64" {
+			datatype = "int64"
+			for j, v := range values {
+				if n, ok := v.(int); ok {
+					values[j] = int64(n)
+				}
+			}
+		}
+		if datatype != "category" {
+			seriesList[i] = newSeries(field.Name, datatype, values)
+			continue
+		}
+
+		// Category values are read as labels, the dictionary keeps the order of
+		// the chunk dictionaries
+		labels := []string{}
+		seen := make(map[string]bool)
+		for _, chunk := range tbl.Column(i).Data().Chunks() {
+			dict := chunk.(*array.Dictionary).Dictionary()
+			for j := 0; j < dict.Len(); j++ {
+				if label := dict.ValueStr(j); !seen[label] {
+					seen[label] = true
+					labels = append(labels, label)
+				}
+			}
+		}
+		col, err := newSeries(field.Name, "string", values).AsCategory(labels...)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %v", field.Name, err)
+		}
+		seriesList[i] = col
+	}
+
+	return Create(seriesList)
+}
+
+// ArrowWriteOptions configures the Arrow IPC writers
+type ArrowWriteOptions struct {
+	BatchSize   int64  // Maximum number of rows per record batch, 0 writes a single batch
+	Compression string // Body compression, one of none, lz4 or zstd
+}
+
+// ToArrow copies the DataFrame into an Arrow record batch. Each Series becomes
+// a nullable field and nil values are marked invalid in the validity bitmap.
+// The caller must Release the returned record.
+func (df *DataFrame) ToArrow() (arrow.RecordBatch, error) {
+	return df.arrowRecord(memory.DefaultAllocator)
+}
+
+// FromArrow copies an Arrow record batch into a new DataFrame
+func FromArrow(rec arrow.RecordBatch) (*DataFrame, error) {
+	return fromArrowRecords(rec.Schema(), []arrow.RecordBatch{rec})
+}
+
+// ReadArrowStream reads every record batch of an Arrow IPC stream into a new
+// DataFrame. Compressed input is decompressed.
+func ReadArrowStream(r io.Reader) (*DataFrame, error) {
+	dr, err := decompress(r)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing arrow stream: %v", err)
+	}
+	defer dr.Close()
+
+	reader, err := ipc.NewReader(dr)
+	if err != nil {
+		return nil, fmt.Errorf("error opening arrow stream: %v", err)
+	}
+	defer reader.Release()
+
+	// The reader reuses its record, so keep our own reference to each batch
+	records := []arrow.RecordBatch{}
+	defer func() {
+		for _, rec := range records {
+			rec.Release()
+		}
+	}()
+	for reader.Next() {
+		rec := reader.RecordBatch()
+		rec.Retain()
+		records = append(records, rec)
+	}
+	if err := reader.Err(); err != nil {
+		return nil, fmt.Errorf("error reading arrow stream: %v", err)
+	}
+
+	return fromArrowRecords(reader.Schema(), records)
+}
+
+// ReadArrowFile reads every record batch of an Arrow IPC file into a new DataFrame
+func ReadArrowFile(r ReaderAtSeeker) (*DataFrame, error) {
+	reader, err := ipc.NewFileReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("error opening arrow file: %v", err)
+	}
+	defer reader.Close()
+
+	records := make([]arrow.RecordBatch, 0, reader.NumRecords())
+	defer func() {
+		for _, rec := range records {
+			rec.Release()
+		}
+	}()
+	for i := 0; i < reader.NumRecords(); i++ {
+		rec, err := reader.RecordBatchAt(i)
+		if err != nil {
+			return nil, fmt.Errorf("error reading arrow file: %v", err)
+		}
+		records = append(records, rec)
+	}
+
+	return fromArrowRecords(reader.Schema(), records)
+}
+
+// WriteArrowStream writes the DataFrame in the Arrow IPC stream format
+func (df *DataFrame) WriteArrowStream(w io.Writer, options ArrowWriteOptions) error {
+	return df.writeArrow(options, func(opts []ipc.Option) (arrowWriter, error) {
+		return ipc.NewWriter(w, opts...), nil
+	})
+}
+
+// WriteArrowFile writes the DataFrame in the Arrow IPC file format
+func (df *DataFrame) WriteArrowFile(w io.Writer, options ArrowWriteOptions) error {
+	return df.writeArrow(options, func(opts []ipc.Option) (arrowWriter, error) {
+		return ipc.NewFileWriter(w, opts...)
+	})
+}
+
+// arrowWriter is the part of the stream and file writers that writeArrow uses
+type arrowWriter interface {
+	Write(rec arrow.RecordBatch) error
+	Close() error
+}
+
+// writeArrow converts the DataFrame and writes it in slices of BatchSize rows
+func (df *DataFrame) writeArrow(options ArrowWriteOptions, newWriter func([]ipc.Option) (arrowWriter, error)) error {
+	rec, err := df.ToArrow()
+	if err != nil {
+		return err
+	}
+	defer rec.Release()
+
+	opts := []ipc.Option{ipc.WithSchema(rec.Schema())}
+	switch options.Compression {
+	case "", "none":
+	case "lz4":
+		opts = append(opts, ipc.WithLZ4())
+	case "zstd":
+		opts = append(opts, ipc.WithZstd())
+	default:
+		return fmt.Errorf("invalid arrow compression: %s", options.Compression)
+	}
+
+	writer, err := newWriter(opts)
+	if err != nil {
+		return fmt.Errorf("error creating arrow writer: %v", err)
+	}
+
+	batchSize := options.BatchSize
+	if batchSize <= 0 || batchSize > rec.NumRows() {
+		batchSize = rec.NumRows()
+	}
+	for offset := int64(0); offset < rec.NumRows(); offset += batchSize {
+		end := offset + batchSize
+		if end > rec.NumRows() {
+			end = rec.NumRows()
+		}
+		slice := rec.NewSlice(offset, end)
+		err := writer.Write(slice)
+		slice.Release()
+		if err != nil {
+			writer.Close()
+			return fmt.Errorf("error writing arrow batch: %v", err)
+		}
+	}
+
+	return writer.Close()
+}
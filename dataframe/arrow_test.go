@@ -0,0 +1,240 @@
+package dataframe
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func TestArrowRecordRoundTrip(t *testing.T) {
+	df := newFrame(t,
+		"n", "int", []interface{}{1, nil, 3},
+		"x", "float", []interface{}{1.5, nil, nil},
+		"s", "string", []interface{}{"a", "b", nil},
+		"b", "bool", []interface{}{nil, true, false},
+	)
+	rec, err := df.ToArrow()
+	if err != nil {
+		t.Fatalf("ToArrow: %v", err)
+	}
+	defer rec.Release()
+
+	wantTypes := []arrow.Type{arrow.INT64, arrow.FLOAT64, arrow.STRING, arrow.BOOL}
+	wantNulls := []int{1, 2, 1, 1}
+	for i, field := range rec.Schema().Fields() {
+		if !field.Nullable || field.Type.ID() != wantTypes[i] {
+			t.Errorf("field %s: got %s nullable %v, want nullable %s", field.Name, field.Type, field.Nullable, wantTypes[i])
+		}
+		if got := rec.Column(i).NullN(); got != wantNulls[i] {
+			t.Errorf("field %s: got %d nulls, want %d", field.Name, got, wantNulls[i])
+		}
+	}
+
+	back, err := FromArrow(rec)
+	if err != nil {
+		t.Fatalf("FromArrow: %v", err)
+	}
+	checkColumn(t, back, "n", "int", []interface{}{1, nil, 3})
+	checkColumn(t, back, "x", "float", []interface{}{1.5, nil, nil})
+	checkColumn(t, back, "s", "string", []interface{}{"a", "b", nil})
+	checkColumn(t, back, "b", "bool", []interface{}{nil, true, false})
+}
+
+// sizedColumns lists a column of every sized numeric datatype, with the
+// extremes of each width and a nil
+var sizedColumns = []struct {
+	datatype string
+	values   []interface{}
+}{
+	{"int8", []interface{}{int8(math.MinInt8), nil, int8(math.MaxInt8)}},
+	{"int16", []interface{}{int16(math.MinInt16), nil, int16(math.MaxInt16)}},
+	{"int32", []interface{}{int32(math.MinInt32), nil, int32(math.MaxInt32)}},
+	{"int64", []interface{}{int64(math.MinInt64), nil, int64(math.MaxInt64)}},
+	{"uint8", []interface{}{uint8(0), nil, uint8(math.MaxUint8)}},
+	{"uint16", []interface{}{uint16(0), nil, uint16(math.MaxUint16)}},
+	{"uint32", []interface{}{uint32(0), nil, uint32(math.MaxUint32)}},
+	{"uint64", []interface{}{uint64(0), nil, uint64(math.MaxUint64)}},
+	{"float32", []interface{}{float32(-1.5), nil, float32(math.MaxFloat32)}},
+	{"int", []interface{}{math.MinInt64, nil, math.MaxInt64}},
+	{"float", []interface{}{-1.5, nil, math.MaxFloat64}},
+}
+
+// sizedFrame builds a DataFrame with one column per entry of sizedColumns,
+// named after its datatype
+func sizedFrame(t *testing.T) *DataFrame {
+	t.Helper()
+	var args []interface{}
+	for _, c := range sizedColumns {
+		args = append(args, c.datatype, c.datatype, c.values)
+	}
+	return newFrame(t, args...)
+}
+
+// checkSizedFrame checks that every column of sizedFrame kept its datatype
+// and values
+func checkSizedFrame(t *testing.T, df *DataFrame) {
+	t.Helper()
+	for _, c := range sizedColumns {
+		checkColumn(t, df, c.datatype, c.datatype, c.values)
+	}
+}
+
+func TestArrowSizedRoundTrip(t *testing.T) {
+	rec, err := sizedFrame(t).ToArrow()
+	if err != nil {
+		t.Fatalf("ToArrow: %v", err)
+	}
+	defer rec.Release()
+	back, err := FromArrow(rec)
+	if err != nil {
+		t.Fatalf("FromArrow: %v", err)
+	}
+	checkSizedFrame(t, back)
+
+	// Without the datatype key an INT64 field is read as "int"
+	schema := arrow.NewSchema([]arrow.Field{{Name: "v", Type: arrow.PrimitiveTypes.Int64, Nullable: true}}, nil)
+	b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer b.Release()
+	b.Field(0).(*array.Int64Builder).Append(7)
+	plain := b.NewRecordBatch()
+	defer plain.Release()
+	df, err := FromArrow(plain)
+	if err != nil {
+		t.Fatalf("FromArrow: %v", err)
+	}
+	checkColumn(t, df, "v", "int", []interface{}{7})
+}
+
+func TestArrowTimes(t *testing.T) {
+	when := time.Date(2024, 3, 1, 12, 30, 0, 5, time.FixedZone("CET", 3600))
+	df := newFrame(t,
+		"t", "datetime", []interface{}{when, nil},
+		"d", "duration", []interface{}{nil, -90 * time.Second},
+	)
+	rec, err := df.ToArrow()
+	if err != nil {
+		t.Fatalf("ToArrow: %v", err)
+	}
+	defer rec.Release()
+	ts, ok := rec.Schema().Field(0).Type.(*arrow.TimestampType)
+	if !ok || ts.Unit != arrow.Nanosecond || ts.TimeZone != "UTC" {
+		t.Errorf("datetime field: got %s", rec.Schema().Field(0).Type)
+	}
+	if got := rec.Schema().Field(1).Type; !arrow.TypeEqual(got, arrow.FixedWidthTypes.Duration_ns) {
+		t.Errorf("duration field: got %s", got)
+	}
+
+	// Times come back as the same instant in UTC
+	back, err := FromArrow(rec)
+	if err != nil {
+		t.Fatalf("FromArrow: %v", err)
+	}
+	checkColumn(t, back, "t", "datetime", []interface{}{when.UTC(), nil})
+	checkColumn(t, back, "d", "duration", []interface{}{nil, -90 * time.Second})
+
+	// Other units are scaled to nanoseconds
+	tests := []struct {
+		name  string
+		dt    arrow.DataType
+		value int64
+		want  interface{}
+	}{
+		{"timestamp seconds", &arrow.TimestampType{Unit: arrow.Second}, 86400, time.Unix(86400, 0).UTC()},
+		{"timestamp millis", &arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "America/New_York"}, 1500, time.Unix(1, 5e8).UTC()},
+		{"duration micros", &arrow.DurationType{Unit: arrow.Microsecond}, 7, 7 * time.Microsecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := arrow.NewSchema([]arrow.Field{{Name: "v", Type: tt.dt, Nullable: true}}, nil)
+			b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+			defer b.Release()
+			switch fb := b.Field(0).(type) {
+			case *array.TimestampBuilder:
+				fb.Append(arrow.Timestamp(tt.value))
+			case *array.DurationBuilder:
+				fb.Append(arrow.Duration(tt.value))
+			}
+			rec := b.NewRecordBatch()
+			defer rec.Release()
+			df, err := FromArrow(rec)
+			if err != nil {
+				t.Fatalf("FromArrow: %v", err)
+			}
+			if got := columnValues(t, df, "v")[0]; got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArrowIPCRoundTrip(t *testing.T) {
+	df := newFrame(t,
+		"n", "int", []interface{}{1, 2, 3, nil, 5},
+		"s", "string", []interface{}{"a", nil, "c", "d", "e"},
+	)
+	tests := []struct {
+		name    string
+		options ArrowWriteOptions
+	}{
+		{"single batch", ArrowWriteOptions{}},
+		{"batches", ArrowWriteOptions{BatchSize: 2}},
+		{"lz4", ArrowWriteOptions{BatchSize: 3, Compression: "lz4"}},
+		{"zstd", ArrowWriteOptions{Compression: "zstd"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stream, file bytes.Buffer
+			if err := df.WriteArrowStream(&stream, tt.options); err != nil {
+				t.Fatalf("WriteArrowStream: %v", err)
+			}
+			if err := df.WriteArrowFile(&file, tt.options); err != nil {
+				t.Fatalf("WriteArrowFile: %v", err)
+			}
+
+			fromStream, err := ReadArrowStream(&stream)
+			if err != nil {
+				t.Fatalf("ReadArrowStream: %v", err)
+			}
+			fromFile, err := ReadArrowFile(bytes.NewReader(file.Bytes()))
+			if err != nil {
+				t.Fatalf("ReadArrowFile: %v", err)
+			}
+			for _, back := range []*DataFrame{fromStream, fromFile} {
+				checkColumn(t, back, "n", "int", []interface{}{1, 2, 3, nil, 5})
+				checkColumn(t, back, "s", "string", []interface{}{"a", nil, "c", "d", "e"})
+			}
+		})
+	}
+}
+
+func TestArrowErrors(t *testing.T) {
+	df := newFrame(t, "n", "int", []interface{}{1})
+	err := df.WriteArrowStream(&bytes.Buffer{}, ArrowWriteOptions{Compression: "snappy"})
+	if err == nil || !strings.Contains(err.Error(), "invalid arrow compression") {
+		t.Errorf("got error %v, want an invalid compression error", err)
+	}
+
+	lists := newFrame(t, "l", "list<int>", []interface{}{[]int{1}})
+	if _, err := lists.ToArrow(); err == nil || !strings.Contains(err.Error(), "column l") {
+		t.Errorf("got error %v, want an unsupported type error", err)
+	}
+
+	old := newFrame(t, "t", "datetime", []interface{}{time.Date(1500, 1, 1, 0, 0, 0, 0, time.UTC)})
+	if _, err := old.ToArrow(); err == nil || !strings.Contains(err.Error(), "out of range") {
+		t.Errorf("got error %v, want an out of range error", err)
+	}
+
+	if _, err := ReadArrowStream(strings.NewReader("not arrow")); err == nil {
+		t.Error("expected an error for an invalid stream")
+	}
+	if _, err := ReadArrowFile(strings.NewReader("not arrow")); err == nil {
+		t.Error("expected an error for an invalid file")
+	}
+}
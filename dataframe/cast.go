@@ -0,0 +1,46 @@
+package dataframe
+
+import (
+	"fmt"
+	"koalas/series"
+)
+
+// Cast converts columns to new datatypes in place, keeping the schema in sync.
+// Types maps column names to their new datatype and every column is converted
+// with Series.AsType in the given mode. In series.CastStrict mode the first
+// value that cannot be converted fails the cast and no column is changed. In
+// series.CastLenient mode such values become nil and are returned, column by
+// column in the order of the DataFrame.
+func (df *DataFrame) Cast(types map[string]string, mode series.CastMode) ([]series.CastError, error) {
+	for name, datatype := range types {
+		if _, exists := df.columns.Get(name); !exists {
+			return nil, fmt.Errorf("column '%s' does not exist in DataFrame", name)
+		}
+		if !series.IsValidDatatype(datatype) {
+			return nil, fmt.Errorf("invalid type for column %s: %s", name, datatype)
+		}
+	}
+
+	// Convert every column before replacing any of them
+	var report []series.CastError
+	converted := make(map[string]*series.Series, len(types))
+	for _, name := range df.columns.Keys() {
+		datatype, exists := types[name]
+		if !exists {
+			continue
+		}
+		col, _ := df.columns.Get(name)
+		result, errs, err := col.AsType(datatype, mode)
+		if err != nil {
+			return nil, err
+		}
+		converted[name] = result
+		report = append(report, errs...)
+	}
+
+	for name, col := range converted {
+		df.columns.Set(name, col)
+		df.schema[name] = col.Datatype
+	}
+	return report, nil
+}
@@ -0,0 +1,49 @@
+package dataframe
+
+import (
+	"koalas/series"
+	"strings"
+	"testing"
+)
+
+func TestCast(t *testing.T) {
+	df := newFrame(t,
+		"a", "string", []interface{}{"1", "x"},
+		"b", "int", []interface{}{1, 0},
+	)
+
+	// A strict cast that fails leaves every column as it was
+	_, err := df.Cast(map[string]string{"b": "bool", "a": "int"}, series.CastStrict)
+	if err == nil || !strings.Contains(err.Error(), `a at position 1`) {
+		t.Fatalf("got error %v, want a failure at position 1", err)
+	}
+	checkColumn(t, df, "a", "string", []interface{}{"1", "x"})
+	checkColumn(t, df, "b", "int", []interface{}{1, 0})
+
+	report, err := df.Cast(map[string]string{"b": "bool", "a": "int"}, series.CastLenient)
+	if err != nil {
+		t.Fatalf("Cast: %v", err)
+	}
+	checkColumn(t, df, "a", "int", []interface{}{1, nil})
+	checkColumn(t, df, "b", "bool", []interface{}{true, false})
+	if len(report) != 1 || report[0].Column != "a" || report[0].Position != 1 {
+		t.Errorf("got report %v", report)
+	}
+
+	tests := []struct {
+		name  string
+		types map[string]string
+		want  string
+	}{
+		{"missing column", map[string]string{"c": "int"}, "column 'c' does not exist"},
+		{"invalid type", map[string]string{"a": "huge"}, "invalid type for column a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := df.Cast(tt.types, series.CastStrict)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
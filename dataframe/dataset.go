@@ -0,0 +1,208 @@
+package dataframe
+
+import (
+	"fmt"
+	"io/fs"
+	"koalas/series"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DatasetOptions configures ReadDataset
+type DatasetOptions struct {
+	Read                ReadOptions // Options passed to ReadFile for every file
+	SourceColumn        string      // When set, adds a string column holding each row's file path
+	AllowMissingColumns bool        // Fill columns missing from some files with nil instead of failing
+}
+
+// DefaultDatasetOptions returns the options used for a typical partitioned dataset
+func DefaultDatasetOptions() DatasetOptions {
+	return DatasetOptions{
+		Read: DefaultReadOptions(),
+	}
+}
+
+// ReadDataset reads every file matched by a glob pattern, or every file below a
+// directory, and unions them into one DataFrame. The schemas of the files are
+// merged first: columns keep the order they are first seen in, and a column whose
+// numeric type differs between files is read as the type series.PromoteType
+// picks, so that "int" in one file and "float" in another is read as "float".
+func ReadDataset(pattern string, format Format, options DatasetOptions) (*DataFrame, error) {
+	paths, err := expandDataset(pattern, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no files match %s", pattern)
+	}
+
+	// Read every file
+	frames := make([]*DataFrame, len(paths))
+	for i, path := range paths {
+		df, err := ReadFile(path, format, options.Read)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %v", path, err)
+		}
+		frames[i] = df
+	}
+
+	columns, err := mergeSchemas(frames, options.AllowMissingColumns)
+	if err != nil {
+		return nil, err
+	}
+	if options.SourceColumn != "" {
+		for _, col := range columns {
+			if col.Name == options.SourceColumn {
+				return nil, fmt.Errorf("column '%s' already exists", options.SourceColumn)
+			}
+		}
+	}
+
+	// Conform each file to the merged schema, collecting the values of every
+	// column so that the result is built once
+	values := make([][]interface{}, len(columns))
+	sources := []interface{}{}
+	for i, df := range frames {
+		if err := df.appendConformed(values, columns); err != nil {
+			return nil, fmt.Errorf("error reading %s: %v", paths[i], err)
+		}
+		if options.SourceColumn != "" {
+			for j := 0; j < df.numRows; j++ {
+				sources = append(sources, paths[i])
+			}
+		}
+	}
+
+	seriesList := make([]*series.Series, 0, len(columns)+1)
+	for i, info := range columns {
+		col, err := newColumnSeries(info.Name, info.DataType, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %v", info.Name, err)
+		}
+		seriesList = append(seriesList, col)
+	}
+	if options.SourceColumn != "" {
+		seriesList = append(seriesList, newSeries(options.SourceColumn, "string", sources))
+	}
+	return Create(seriesList)
+}
+
+// expandDataset lists the files of a dataset in name order. A directory is
+// walked recursively, keeping the files whose format matches.
+func expandDataset(pattern string, format Format) ([]string, error) {
+	info, err := os.Stat(pattern)
+	if err != nil || !info.IsDir() {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %v", pattern, err)
+		}
+		sort.Strings(paths)
+		return paths, nil
+	}
+
+	paths := []string{}
+	err = filepath.WalkDir(pattern, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		// Skip files that are not part of the dataset, such as _SUCCESS markers
+		detected, err := formatFromPath(path)
+		if err != nil || (format != "" && detected != format) {
+			return nil
+		}
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// mergeSchemas combines the columns of every DataFrame into one schema. A
+// column holding only nil in a file does not constrain its datatype there, and
+// a column that is nil in every file is typed as string.
+func mergeSchemas(frames []*DataFrame, allowMissing bool) ([]ColumnInfo, error) {
+	columns := []ColumnInfo{}
+	positions := make(map[string]int)
+	counts := make(map[string]int)
+
+	for _, df := range frames {
+		for _, name := range df.columns.Keys() {
+			counts[name]++
+			datatype := df.schema[name]
+			if col, _ := df.columns.Get(name); col.NullCount() == col.Len() {
+				datatype = ""
+			}
+
+			pos, exists := positions[name]
+			if !exists {
+				positions[name] = len(columns)
+				columns = append(columns, ColumnInfo{Name: name, DataType: datatype})
+				continue
+			}
+			if datatype == "" {
+				continue
+			}
+			if columns[pos].DataType == "" {
+				columns[pos].DataType = datatype
+				continue
+			}
+
+			merged, ok := series.PromoteType(columns[pos].DataType, datatype)
+			if !ok {
+				return nil, fmt.Errorf("data type mismatch for column %s: %s != %s",
+					name, columns[pos].DataType, datatype)
+			}
+			columns[pos].DataType = merged
+		}
+	}
+
+	for i := range columns {
+		if columns[i].DataType == "" {
+			columns[i].DataType = "string"
+		}
+	}
+
+	if !allowMissing {
+		for _, col := range columns {
+			if counts[col.Name] != len(frames) {
+				return nil, fmt.Errorf("column '%s' is missing from %d of %d files",
+					col.Name, len(frames)-counts[col.Name], len(frames))
+			}
+		}
+	}
+	return columns, nil
+}
+
+// appendConformed appends the values of the given columns to values, converting
+// them to the merged datatypes and filling missing columns with nil. Category
+// values are appended as their labels, so that newColumnSeries can encode the
+// values of every file with one dictionary.
+func (df *DataFrame) appendConformed(values [][]interface{}, columns []ColumnInfo) error {
+	for i, info := range columns {
+		col, exists := df.columns.Get(info.Name)
+		if !exists {
+			values[i] = append(values[i], make([]interface{}, df.numRows)...)
+			continue
+		}
+		for j, entry := range col.Data {
+			value := entry.Value
+			if c, ok := value.(series.Category); ok && info.DataType == "category" {
+				values[i] = append(values[i], c.Label())
+				continue
+			}
+			value, err := series.ConvertValue(value, readDatatype(info.DataType))
+			if err != nil {
+				return fmt.Errorf("column %s, row %d: %v", info.Name, j, err)
+			}
+			values[i] = append(values[i], value)
+		}
+	}
+	return nil
+}
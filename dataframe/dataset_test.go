@@ -0,0 +1,190 @@
+package dataframe
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFiles writes each file below dir, creating its directories
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadDataset(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"year=2023/part-0.csv": "id,x,note\n1,,a\n2,,b\n",
+		"year=2024/part-0.csv": "id,x,note\n3,1.5,\n",
+		"year=2024/part-1.csv": "id,x,note\n4,2,c\n",
+		"year=2024/_SUCCESS":   "",
+	})
+
+	df, err := ReadDataset(dir, "", DatasetOptions{Read: DefaultReadOptions(), SourceColumn: "source"})
+	if err != nil {
+		t.Fatalf("ReadDataset: %v", err)
+	}
+	checkColumn(t, df, "id", "int", []interface{}{1, 2, 3, 4})
+	// x is empty in the first file, so the other files decide its type
+	checkColumn(t, df, "x", "float", []interface{}{nil, nil, 1.5, 2.0})
+	checkColumn(t, df, "note", "string", []interface{}{"a", "b", nil, "c"})
+	sources := columnValues(t, df, "source")
+	if len(sources) != 4 || !strings.HasSuffix(sources[0].(string), "part-0.csv") || !strings.HasSuffix(sources[3].(string), "part-1.csv") {
+		t.Errorf("sources: got %v", sources)
+	}
+
+	glob, err := ReadDataset(filepath.Join(dir, "year=2024", "*.csv"), "", DefaultDatasetOptions())
+	if err != nil {
+		t.Fatalf("ReadDataset: %v", err)
+	}
+	checkColumn(t, glob, "id", "int", []interface{}{3, 4})
+}
+
+func TestReadDatasetMissingColumns(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.csv": "id\n1\n",
+		"b.csv": "id,extra\n2,x\n",
+	})
+
+	_, err := ReadDataset(dir, FormatCSV, DefaultDatasetOptions())
+	if err == nil || !strings.Contains(err.Error(), "column 'extra' is missing from 1 of 2 files") {
+		t.Errorf("got error %v, want a missing column error", err)
+	}
+
+	options := DefaultDatasetOptions()
+	options.AllowMissingColumns = true
+	df, err := ReadDataset(dir, FormatCSV, options)
+	if err != nil {
+		t.Fatalf("ReadDataset: %v", err)
+	}
+	if got := df.columns.Keys(); !reflect.DeepEqual(got, []string{"id", "extra"}) {
+		t.Errorf("columns: got %v", got)
+	}
+	checkColumn(t, df, "extra", "string", []interface{}{nil, "x"})
+}
+
+func TestMergeSchemas(t *testing.T) {
+	tests := []struct {
+		name    string
+		frames  []*DataFrame
+		want    []ColumnInfo
+		wantErr string
+	}{
+		{
+			name: "promotes numbers",
+			frames: []*DataFrame{
+				newFrame(t, "a", "int", []interface{}{1}),
+				newFrame(t, "a", "float", []interface{}{1.5}),
+			},
+			want: []ColumnInfo{{Name: "a", DataType: "float"}},
+		},
+		{
+			name: "all null column is untyped",
+			frames: []*DataFrame{
+				newFrame(t, "a", "string", []interface{}{nil}),
+				newFrame(t, "a", "int", []interface{}{1}),
+				newFrame(t, "a", "bool", []interface{}{nil, nil}),
+			},
+			want: []ColumnInfo{{Name: "a", DataType: "int"}},
+		},
+		{
+			name: "null in every file",
+			frames: []*DataFrame{
+				newFrame(t, "a", "int", []interface{}{nil}),
+				newFrame(t, "a", "float", []interface{}{nil}),
+			},
+			want: []ColumnInfo{{Name: "a", DataType: "string"}},
+		},
+		{
+			name: "mismatch",
+			frames: []*DataFrame{
+				newFrame(t, "a", "string", []interface{}{"x"}),
+				newFrame(t, "a", "int", []interface{}{1}),
+			},
+			wantErr: "data type mismatch for column a: string != int",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mergeSchemas(tt.frames, false)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("mergeSchemas: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadDatasetErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.csv": "source\n1\n"})
+
+	tests := []struct {
+		name    string
+		pattern string
+		options DatasetOptions
+		want    string
+	}{
+		{"no files", filepath.Join(dir, "*.parquet"), DefaultDatasetOptions(), "no files match"},
+		{"bad pattern", filepath.Join(dir, "["), DefaultDatasetOptions(), "invalid pattern"},
+		{"source column exists", dir, DatasetOptions{Read: DefaultReadOptions(), SourceColumn: "source"}, "column 'source' already exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadDataset(tt.pattern, "", tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadDatasetCategories(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.csv": "k\nx\ny\n",
+		"b.csv": "k\nz\nx\n",
+	})
+	options := DefaultDatasetOptions()
+	options.Read.CSV.Schema = map[string]string{"k": "category"}
+	df, err := ReadDataset(dir, "", options)
+	if err != nil {
+		t.Fatalf("ReadDataset: %v", err)
+	}
+	col, _ := df.columns.Get("k")
+	if col.Datatype != "category" {
+		t.Fatalf("got datatype %s, want category", col.Datatype)
+	}
+	labels, err := col.Cat().AsString()
+	if err != nil {
+		t.Fatalf("AsString: %v", err)
+	}
+	got := make([]interface{}, labels.Len())
+	for i, entry := range labels.Data {
+		got[i] = entry.Value
+	}
+	if !reflect.DeepEqual(got, []interface{}{"x", "y", "z", "x"}) {
+		t.Errorf("got %v", got)
+	}
+}
@@ -2,6 +2,7 @@ package dataframe
 
 import (
 	"fmt"
+	"koalas/series"
 	"strings"
 )
 
@@ -20,35 +21,7 @@ func (df *DataFrame) Display(showSchema bool) {
 	colNames := df.columns.Keys()
 
 	// Find maximum width for each column
-	maxWidths := make([]int, len(colNames)+1) // +1 for index column
-	// Set width for index column
-	maxWidths[0] = len("Index")
-
-	// Check data widths for index column
-	for i := 0; i < df.numRows; i++ {
-		width := len(fmt.Sprintf("%d", i))
-		if width > maxWidths[0] {
-			maxWidths[0] = width
-		}
-	}
-
-	// Check widths for other columns
-	for i, name := range colNames {
-		// Start with header width
-		maxWidths[i+1] = len(name)
-
-		// Check data widths
-		if col, exists := df.columns.Get(name); exists {
-			for j := 0; j < col.Len(); j++ {
-				if val, err := col.Get(j); err == nil {
-					width := len(fmt.Sprintf("%v", val))
-					if width > maxWidths[i+1] {
-						maxWidths[i+1] = width
-					}
-				}
-			}
-		}
-	}
+	maxWidths := df.columnWidths(colNames, series.FormatValue)
 
 	// Print headers
 	fmt.Print("\n")
@@ -74,7 +47,7 @@ func (df *DataFrame) Display(showSchema bool) {
 			fmt.Print(" | ")
 			if col, exists := df.columns.Get(name); exists {
 				if val, err := col.Get(row); err == nil {
-					fmt.Printf("%-*v", maxWidths[i+1], val)
+					fmt.Printf("%-*s", maxWidths[i+1], series.FormatValue(val))
 				} else {
 					fmt.Printf("%-*s", maxWidths[i+1], "ERROR")
 				}
@@ -85,6 +58,42 @@ func (df *DataFrame) Display(showSchema bool) {
 	fmt.Print("\n")
 }
 
+// columnWidths returns the width of the index column followed by the width of
+// each named column, measuring every value as text produced by format
+func (df *DataFrame) columnWidths(colNames []string, format func(interface{}) string) []int {
+	maxWidths := make([]int, len(colNames)+1) // +1 for index column
+	// Set width for index column
+	maxWidths[0] = len("Index")
+
+	// Check data widths for index column
+	for i := 0; i < df.numRows; i++ {
+		width := len(fmt.Sprintf("%d", i))
+		if width > maxWidths[0] {
+			maxWidths[0] = width
+		}
+	}
+
+	// Check widths for other columns
+	for i, name := range colNames {
+		// Start with header width
+		maxWidths[i+1] = len(format(name))
+
+		// Check data widths
+		if col, exists := df.columns.Get(name); exists {
+			for j := 0; j < col.Len(); j++ {
+				if val, err := col.Get(j); err == nil {
+					width := len(format(val))
+					if width > maxWidths[i+1] {
+						maxWidths[i+1] = width
+					}
+				}
+			}
+		}
+	}
+
+	return maxWidths
+}
+
 func (df *DataFrame) DisplaySchema() {
 	// Find the longest column name for alignment
 	maxNameLen := 0
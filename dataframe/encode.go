@@ -0,0 +1,327 @@
+package dataframe
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"koalas/series"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec selects the binary encoding used by Encode and Decode
+type Codec string
+
+const (
+	CodecGob     Codec = "gob"
+	CodecMsgpack Codec = "msgpack"
+)
+
+// frameWire is the binary wire format of a DataFrame. Values are stored in
+// typed slices so that neither codec has to guess the Go type of a value.
+type frameWire struct {
+	NumRows int               `msgpack:"rows"`
+	Schema  map[string]string `msgpack:"schema"`
+	Columns []columnWire      `msgpack:"columns"`
+}
+
+// columnWire is the binary wire format of a Series
+type columnWire struct {
+	Name     string    `msgpack:"name"`
+	Datatype string    `msgpack:"datatype"`
+	Indices  []int64   `msgpack:"indices"`
+	Valid    []bool    `msgpack:"valid"`
+	Ints     []int64   `msgpack:"ints,omitempty"`
+	Uints    []uint64  `msgpack:"uints,omitempty"`
+	Floats   []float64 `msgpack:"floats,omitempty"`
+	Strings  []string  `msgpack:"strings,omitempty"`
+	Bools    []bool    `msgpack:"bools,omitempty"`
+	Times    [][]byte  `msgpack:"times,omitempty"` // time.Time.MarshalBinary, which keeps the zone offset
+
+	// Dictionary of a category column, whose codes are stored in Ints
+	Categories []string `msgpack:"categories,omitempty"`
+}
+
+// Encode writes the DataFrame with the given codec. Column order, the schema,
+// nil values and the Index of every Entry are kept exactly.
+func (df *DataFrame) Encode(w io.Writer, codec Codec) error {
+	wire := frameWire{
+		NumRows: df.numRows,
+		Schema:  make(map[string]string, df.numCols),
+		Columns: make([]columnWire, 0, df.numCols),
+	}
+	for _, name := range df.columns.Keys() {
+		col, _ := df.columns.Get(name)
+		cw, err := encodeColumnWire(col)
+		if err != nil {
+			return fmt.Errorf("column %s: %v", name, err)
+		}
+		wire.Schema[name] = col.Datatype
+		wire.Columns = append(wire.Columns, cw)
+	}
+
+	switch codec {
+	case CodecGob:
+		return gob.NewEncoder(w).Encode(wire)
+	case CodecMsgpack:
+		return msgpack.NewEncoder(w).Encode(wire)
+	}
+	return fmt.Errorf("invalid codec: %s", codec)
+}
+
+// Decode replaces the contents of the DataFrame with one read by the given codec
+func (df *DataFrame) Decode(r io.Reader, codec Codec) error {
+	var wire frameWire
+	var err error
+	switch codec {
+	case CodecGob:
+		err = gob.NewDecoder(r).Decode(&wire)
+	case CodecMsgpack:
+		err = msgpack.NewDecoder(r).Decode(&wire)
+	default:
+		return fmt.Errorf("invalid codec: %s", codec)
+	}
+	if err != nil {
+		return fmt.Errorf("error decoding %s: %v", codec, err)
+	}
+
+	if wire.NumRows < 0 || (len(wire.Columns) == 0 && wire.NumRows != 0) {
+		return fmt.Errorf("invalid row count: %d", wire.NumRows)
+	}
+
+	// The schema is rebuilt from the decoded columns, as Create does, and the
+	// one on the wire must describe exactly those columns
+	result := &DataFrame{
+		columns: NewOrderedMap(),
+		numRows: wire.NumRows,
+		numCols: len(wire.Columns),
+		schema:  make(map[string]string, len(wire.Columns)),
+	}
+	for _, cw := range wire.Columns {
+		col, err := decodeColumnWire(cw)
+		if err != nil {
+			return fmt.Errorf("column %s: %v", cw.Name, err)
+		}
+		if col.Len() != wire.NumRows {
+			return fmt.Errorf("series length mismatch: expected %d rows, got %d", wire.NumRows, col.Len())
+		}
+		if _, exists := result.columns.Get(cw.Name); exists {
+			return fmt.Errorf("duplicate series name: %s", cw.Name)
+		}
+		if datatype, exists := wire.Schema[cw.Name]; !exists || datatype != col.Datatype {
+			return fmt.Errorf("schema mismatch for column %s: schema has %q, column has %s", cw.Name, datatype, col.Datatype)
+		}
+		result.columns.Set(cw.Name, col)
+		result.schema[cw.Name] = col.Datatype
+	}
+	if len(wire.Schema) != len(result.schema) {
+		for name := range wire.Schema {
+			if _, exists := result.schema[name]; !exists {
+				return fmt.Errorf("schema mismatch: column '%s' does not exist", name)
+			}
+		}
+	}
+
+	*df = *result
+	return nil
+}
+
+// encodeColumnWire copies a Series into its wire format
+func encodeColumnWire(col *series.Series) (columnWire, error) {
+	rows := len(col.Data)
+	cw := columnWire{
+		Name:     col.Name,
+		Datatype: col.Datatype,
+		Indices:  make([]int64, rows),
+		Valid:    make([]bool, rows),
+	}
+
+	for i, entry := range col.Data {
+		cw.Indices[i] = int64(entry.Index)
+		cw.Valid[i] = entry.Value != nil
+	}
+
+	// Values, with nil stored as the zero value
+	switch col.Datatype {
+	case "int":
+		cw.Ints = make([]int64, rows)
+		for i, entry := range col.Data {
+			v, _ := entry.Value.(int)
+			cw.Ints[i] = int64(v)
+		}
+	case "duration":
+		cw.Ints = make([]int64, rows)
+		for i, entry := range col.Data {
+			v, _ := entry.Value.(time.Duration)
+			cw.Ints[i] = int64(v)
+		}
+	case "int8", "int16", "int32", "int64":
+		cw.Ints = make([]int64, rows)
+		for i, entry := range col.Data {
+			v, _ := series.ConvertValue(entry.Value, "int64")
+			cw.Ints[i], _ = v.(int64)
+		}
+	case "uint8", "uint16", "uint32", "uint64":
+		cw.Uints = make([]uint64, rows)
+		for i, entry := range col.Data {
+			v, _ := series.ConvertValue(entry.Value, "uint64")
+			cw.Uints[i], _ = v.(uint64)
+		}
+	case "float":
+		cw.Floats = make([]float64, rows)
+		for i, entry := range col.Data {
+			cw.Floats[i], _ = entry.Value.(float64)
+		}
+	case "float32":
+		cw.Floats = make([]float64, rows)
+		for i, entry := range col.Data {
+			v, _ := series.ConvertValue(entry.Value, "float")
+			cw.Floats[i], _ = v.(float64)
+		}
+	case "string":
+		cw.Strings = make([]string, rows)
+		for i, entry := range col.Data {
+			cw.Strings[i], _ = entry.Value.(string)
+		}
+	case "bool":
+		cw.Bools = make([]bool, rows)
+		for i, entry := range col.Data {
+			cw.Bools[i], _ = entry.Value.(bool)
+		}
+	case "category":
+		categories, err := col.Cat().Dictionary()
+		if err != nil {
+			return cw, err
+		}
+		cw.Categories = categories.Labels()
+		cw.Ints = make([]int64, rows)
+		for i, entry := range col.Data {
+			if v, ok := entry.Value.(series.Category); ok {
+				cw.Ints[i] = int64(v.Code())
+			}
+		}
+	case "datetime":
+		cw.Times = make([][]byte, rows)
+		for i, entry := range col.Data {
+			v, _ := entry.Value.(time.Time)
+			raw, err := v.MarshalBinary()
+			if err != nil {
+				return cw, err
+			}
+			cw.Times[i] = raw
+		}
+	default:
+		if !series.IsDecimalDatatype(col.Datatype) {
+			return cw, fmt.Errorf("unsupported type for encoding: %s", col.Datatype)
+		}
+		// Decimals are kept as text so that no digits are lost
+		cw.Strings = make([]string, rows)
+		for i, entry := range col.Data {
+			if v, ok := entry.Value.(series.Decimal); ok {
+				cw.Strings[i] = v.String()
+			}
+		}
+	}
+	return cw, nil
+}
+
+// decodeColumnWire rebuilds a Series from its wire format
+func decodeColumnWire(cw columnWire) (*series.Series, error) {
+	rows := len(cw.Indices)
+	if len(cw.Valid) != rows {
+		return nil, fmt.Errorf("validity length mismatch: expected %d, got %d", rows, len(cw.Valid))
+	}
+
+	var valuesLen int
+	switch cw.Datatype {
+	case "int", "duration", "int8", "int16", "int32", "int64", "category":
+		valuesLen = len(cw.Ints)
+	case "uint8", "uint16", "uint32", "uint64":
+		valuesLen = len(cw.Uints)
+	case "float", "float32":
+		valuesLen = len(cw.Floats)
+	case "string":
+		valuesLen = len(cw.Strings)
+	case "bool":
+		valuesLen = len(cw.Bools)
+	case "datetime":
+		valuesLen = len(cw.Times)
+	default:
+		if !series.IsDecimalDatatype(cw.Datatype) {
+			return nil, fmt.Errorf("unsupported type for decoding: %s", cw.Datatype)
+		}
+		valuesLen = len(cw.Strings)
+	}
+	// Empty slices are dropped by omitempty, so a column of length zero has none
+	if valuesLen != rows {
+		return nil, fmt.Errorf("values length mismatch: expected %d, got %d", rows, valuesLen)
+	}
+
+	categories, err := series.NewCategories(cw.Categories)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]series.Entry, rows)
+	for i := range data {
+		data[i].Index = int(cw.Indices[i])
+		if !cw.Valid[i] {
+			continue
+		}
+		switch cw.Datatype {
+		case "int":
+			data[i].Value = int(cw.Ints[i])
+		case "duration":
+			data[i].Value = time.Duration(cw.Ints[i])
+		case "category":
+			value := categories.Value(int(cw.Ints[i]))
+			if value == nil {
+				return nil, fmt.Errorf("category code out of range: %d", cw.Ints[i])
+			}
+			data[i].Value = value
+		case "int8", "int16", "int32", "int64":
+			value, err := series.ConvertValue(cw.Ints[i], cw.Datatype)
+			if err != nil {
+				return nil, err
+			}
+			data[i].Value = value
+		case "uint8", "uint16", "uint32", "uint64":
+			value, err := series.ConvertValue(cw.Uints[i], cw.Datatype)
+			if err != nil {
+				return nil, err
+			}
+			data[i].Value = value
+		case "float":
+			data[i].Value = cw.Floats[i]
+		case "float32":
+			data[i].Value = float32(cw.Floats[i])
+		case "string":
+			data[i].Value = cw.Strings[i]
+		case "bool":
+			data[i].Value = cw.Bools[i]
+		case "datetime":
+			var t time.Time
+			if err := t.UnmarshalBinary(cw.Times[i]); err != nil {
+				return nil, err
+			}
+			data[i].Value = t
+		default:
+			d, err := series.ParseDecimal(cw.Strings[i])
+			if err != nil {
+				return nil, err
+			}
+			value, err := series.ConvertValue(d, cw.Datatype)
+			if err != nil {
+				return nil, err
+			}
+			data[i].Value = value
+		}
+	}
+
+	return &series.Series{
+		Name:     cw.Name,
+		Datatype: cw.Datatype,
+		Data:     data,
+	}, nil
+}
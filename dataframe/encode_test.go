@@ -0,0 +1,135 @@
+package dataframe
+
+import (
+	"bytes"
+	"encoding/gob"
+	"koalas/series"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestEncodeRoundTrip(t *testing.T) {
+	when := time.Date(2024, 3, 1, 12, 30, 0, 5, time.FixedZone("CET", 3600))
+	cats, err := newSeries("c", "string", []interface{}{"x", nil, "y"}).AsCategory()
+	if err != nil {
+		t.Fatalf("AsCategory: %v", err)
+	}
+	df := newFrame(t,
+		"n", "int", []interface{}{1, nil, -3},
+		"i16", "int16", []interface{}{int16(-2), nil, int16(7)},
+		"u", "uint64", []interface{}{uint64(1 << 63), uint64(0), nil},
+		"x", "float", []interface{}{1.5, nil, 0.0},
+		"f32", "float32", []interface{}{float32(0.25), nil, nil},
+		"s", "string", []interface{}{"a", "", nil},
+		"b", "bool", []interface{}{nil, true, false},
+		"t", "datetime", []interface{}{when, nil, time.Time{}},
+		"d", "duration", []interface{}{time.Second, nil, -time.Minute},
+		"dec", "decimal(6,3)", []interface{}{mustDecimal(t, 123456, 3), nil, mustDecimal(t, -1, 3)},
+	)
+	if err := df.AddColumn("c", cats); err != nil {
+		t.Fatalf("AddColumn: %v", err)
+	}
+	// Give the entries an Index lineage that differs from their positions
+	for _, name := range df.columns.Keys() {
+		col, _ := df.columns.Get(name)
+		for i := range col.Data {
+			col.Data[i].Index = 10 * (i + 1)
+		}
+	}
+
+	for _, codec := range []Codec{CodecGob, CodecMsgpack} {
+		t.Run(string(codec), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := df.Encode(&buf, codec); err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			var back DataFrame
+			if err := back.Decode(&buf, codec); err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			for _, name := range df.columns.Keys() {
+				want, _ := df.columns.Get(name)
+				got, _ := back.columns.Get(name)
+				if back.schema[name] != want.Datatype || got.Datatype != want.Datatype || got.Len() != want.Len() {
+					t.Fatalf("column %s: got %s (schema %s), want %s", name, got.Datatype, back.schema[name], want.Datatype)
+				}
+				for i := range want.Data {
+					if got.Data[i].Index != want.Data[i].Index || !series.Equal(got.Data[i].Value, want.Data[i].Value) {
+						t.Errorf("column %s, row %d: got %+v, want %+v", name, i, got.Data[i], want.Data[i])
+					}
+				}
+			}
+			if tm := columnValues(t, &back, "t")[0].(time.Time); !tm.Equal(when) || tm.Format(time.RFC3339) != when.Format(time.RFC3339) {
+				t.Errorf("datetime lost its zone: %v", tm)
+			}
+		})
+	}
+}
+
+func TestDecodeInvalidWire(t *testing.T) {
+	column := columnWire{Name: "a", Datatype: "int", Indices: []int64{0}, Valid: []bool{true}, Ints: []int64{1}}
+	tests := []struct {
+		name string
+		wire frameWire
+		want string
+	}{
+		{"valid", frameWire{NumRows: 1, Schema: map[string]string{"a": "int"}, Columns: []columnWire{column}}, ""},
+		{"schema type", frameWire{NumRows: 1, Schema: map[string]string{"a": "string"}, Columns: []columnWire{column}}, "schema mismatch for column a"},
+		{"schema missing column", frameWire{NumRows: 1, Schema: map[string]string{}, Columns: []columnWire{column}}, "schema mismatch for column a"},
+		{"schema extra column", frameWire{NumRows: 1, Schema: map[string]string{"a": "int", "b": "int"}, Columns: []columnWire{column}}, "column 'b' does not exist"},
+		{"row count", frameWire{NumRows: 2, Schema: map[string]string{"a": "int"}, Columns: []columnWire{column}}, "series length mismatch"},
+		{"negative rows", frameWire{NumRows: -1}, "invalid row count"},
+		{"rows without columns", frameWire{NumRows: 3}, "invalid row count"},
+		{"duplicate", frameWire{NumRows: 1, Schema: map[string]string{"a": "int"}, Columns: []columnWire{column, column}}, "duplicate series name"},
+		{"values length", frameWire{NumRows: 1, Schema: map[string]string{"a": "int"}, Columns: []columnWire{{Name: "a", Datatype: "int", Indices: []int64{0}, Valid: []bool{true}}}}, "values length mismatch"},
+		{"unsupported type", frameWire{NumRows: 1, Schema: map[string]string{"a": "list<int>"}, Columns: []columnWire{{Name: "a", Datatype: "list<int>", Indices: []int64{0}, Valid: []bool{false}}}}, "unsupported type"},
+		{"category code", frameWire{NumRows: 1, Schema: map[string]string{"a": "category"}, Columns: []columnWire{{Name: "a", Datatype: "category", Indices: []int64{0}, Valid: []bool{true}, Ints: []int64{4}, Categories: []string{"x"}}}}, "category code out of range"},
+	}
+
+	for _, tt := range tests {
+		for _, codec := range []Codec{CodecGob, CodecMsgpack} {
+			t.Run(tt.name+"/"+string(codec), func(t *testing.T) {
+				var buf bytes.Buffer
+				var err error
+				if codec == CodecGob {
+					err = gob.NewEncoder(&buf).Encode(tt.wire)
+				} else {
+					err = msgpack.NewEncoder(&buf).Encode(tt.wire)
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var df DataFrame
+				err = df.Decode(&buf, codec)
+				if tt.want == "" {
+					if err != nil {
+						t.Fatalf("Decode: %v", err)
+					}
+					checkColumn(t, &df, "a", "int", []interface{}{1})
+					return
+				}
+				if err == nil || !strings.Contains(err.Error(), tt.want) {
+					t.Errorf("got error %v, want %q", err, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	df := newFrame(t, "a", "int", []interface{}{1})
+	if err := df.Encode(&bytes.Buffer{}, "json"); err == nil || !strings.Contains(err.Error(), "invalid codec") {
+		t.Errorf("got error %v, want an invalid codec error", err)
+	}
+	var back DataFrame
+	if err := back.Decode(strings.NewReader("garbage"), CodecGob); err == nil {
+		t.Error("expected an error decoding garbage")
+	}
+	if err := back.Decode(strings.NewReader(""), "json"); err == nil || !strings.Contains(err.Error(), "invalid codec") {
+		t.Errorf("got error %v, want an invalid codec error", err)
+	}
+}
@@ -0,0 +1,253 @@
+package dataframe
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ExcelReadOptions configures how ReadExcel parses a sheet
+type ExcelReadOptions struct {
+	Header     bool              // Whether the first row of the range holds the column names
+	Range      string            // Cell range to read such as "B2:F100", empty reads the used area
+	NullValues []string          // Cell values that are read as nil
+	Schema     map[string]string // Explicit datatypes that override inference
+}
+
+// DefaultExcelReadOptions returns the options used for a typical spreadsheet
+func DefaultExcelReadOptions() ExcelReadOptions {
+	return ExcelReadOptions{
+		Header:     true,
+		NullValues: []string{"", "NA", "#N/A"},
+	}
+}
+
+// ReadExcel reads one sheet of an .xlsx workbook into a new DataFrame, inferring
+// the datatype of each column. An empty sheet name reads the first sheet.
+func ReadExcel(path string, sheet string, options ExcelReadOptions) (*DataFrame, error) {
+	r, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return readExcel(r, sheet, options)
+}
+
+// ReadExcelSheets reads every sheet of an .xlsx workbook, keyed by sheet name.
+// Empty sheets give a DataFrame without columns.
+func ReadExcelSheets(path string, options ExcelReadOptions) (map[string]*DataFrame, error) {
+	r, err := openFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("error opening workbook: %v", err)
+	}
+	defer f.Close()
+
+	result := make(map[string]*DataFrame)
+	for _, sheet := range f.GetSheetList() {
+		df, err := readExcelSheet(f, sheet, options)
+		if err != nil {
+			return nil, err
+		}
+		result[sheet] = df
+	}
+	return result, nil
+}
+
+// WriteExcel writes each DataFrame to its own sheet of a new .xlsx workbook,
+// with the column names in the first row. Sheets are added in name order.
+func WriteExcel(path string, sheets map[string]*DataFrame) error {
+	w, err := createFile(path)
+	if err != nil {
+		return err
+	}
+
+	err = writeExcel(w, sheets)
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// readExcel reads one sheet of a workbook, an empty sheet name reads the first sheet
+func readExcel(r io.Reader, sheet string, options ExcelReadOptions) (*DataFrame, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("error opening workbook: %v", err)
+	}
+	defer f.Close()
+
+	if sheet == "" {
+		sheet = f.GetSheetName(0)
+	}
+	return readExcelSheet(f, sheet, options)
+}
+
+// writeExcel writes each DataFrame to its own sheet of a new workbook
+func writeExcel(w io.Writer, sheets map[string]*DataFrame) error {
+	if len(sheets) == 0 {
+		return fmt.Errorf("no sheets to write")
+	}
+
+	names := make([]string, 0, len(sheets))
+	for name := range sheets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	for i, name := range names {
+		// A new workbook starts with one sheet, rename it rather than adding another
+		if i == 0 {
+			if err := f.SetSheetName(f.GetSheetName(0), name); err != nil {
+				return fmt.Errorf("sheet %s: %v", name, err)
+			}
+		} else if _, err := f.NewSheet(name); err != nil {
+			return fmt.Errorf("sheet %s: %v", name, err)
+		}
+
+		if err := sheets[name].writeExcelSheet(f, name); err != nil {
+			return fmt.Errorf("sheet %s: %v", name, err)
+		}
+	}
+
+	return f.Write(w)
+}
+
+// readExcelSheet reads the requested range of a sheet into a DataFrame
+func readExcelSheet(f *excelize.File, sheet string, options ExcelReadOptions) (*DataFrame, error) {
+	if idx, err := f.GetSheetIndex(sheet); err != nil || idx < 0 {
+		return nil, fmt.Errorf("sheet '%s' does not exist in workbook", sheet)
+	}
+
+	// Raw values keep numbers free of display formatting
+	rows, err := f.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, fmt.Errorf("error reading sheet %s: %v", sheet, err)
+	}
+
+	// Work out the bounds of the range, by default the used area of the sheet
+	startCol, startRow, endCol, endRow := 1, 1, 0, len(rows)
+	for _, row := range rows {
+		if len(row) > endCol {
+			endCol = len(row)
+		}
+	}
+	if options.Range != "" {
+		startCol, startRow, endCol, endRow, err = parseExcelRange(options.Range)
+		if err != nil {
+			return nil, err
+		}
+		if endRow < startRow || endCol < startCol {
+			return nil, fmt.Errorf("invalid cell range: %s", options.Range)
+		}
+	}
+	// A sheet without cells gives a DataFrame without columns
+	if endRow < startRow || endCol < startCol {
+		return Create(nil)
+	}
+
+	// Copy the range into a grid, padding the short rows GetRows returns
+	grid := make([][]string, endRow-startRow+1)
+	for r := range grid {
+		grid[r] = make([]string, endCol-startCol+1)
+		rowNum := startRow + r
+		if rowNum > len(rows) {
+			continue
+		}
+		for c := range grid[r] {
+			colNum := startCol + c
+			if colNum > len(rows[rowNum-1]) {
+				continue
+			}
+			value := rows[rowNum-1][colNum-1]
+
+			// Raw boolean cells read as 1 and 0, so check the cell type
+			if value == "1" || value == "0" {
+				cell, _ := excelize.CoordinatesToCellName(colNum, rowNum)
+				if cellType, err := f.GetCellType(sheet, cell); err == nil && cellType == excelize.CellTypeBool {
+					value = strconv.FormatBool(value == "1")
+				}
+			}
+			grid[r][c] = value
+		}
+	}
+
+	// Work out the column names, naming blank header cells after their position
+	var names []string
+	if options.Header {
+		names = grid[0]
+		grid = grid[1:]
+		for i, name := range names {
+			if strings.TrimSpace(name) == "" {
+				names[i] = fmt.Sprintf("column_%d", i)
+			}
+		}
+	} else {
+		names = make([]string, endCol-startCol+1)
+		for i := range names {
+			names[i], _ = excelize.ColumnNumberToName(startCol + i)
+		}
+	}
+
+	// Transpose the grid into raw string columns
+	raw := make([][]string, len(names))
+	for i := range raw {
+		raw[i] = make([]string, len(grid))
+		for j, row := range grid {
+			raw[i][j] = row[i]
+		}
+	}
+
+	return fromStrings(names, raw, options.NullValues, options.Schema, nil)
+}
+
+// parseExcelRange converts a range such as "B2:F100" into 1-based coordinates
+func parseExcelRange(cellRange string) (int, int, int, int, error) {
+	from, to, found := strings.Cut(cellRange, ":")
+	if !found {
+		return 0, 0, 0, 0, fmt.Errorf("invalid cell range: %s", cellRange)
+	}
+
+	startCol, startRow, err := excelize.CellNameToCoordinates(from)
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("invalid cell range: %s", cellRange)
+	}
+	endCol, endRow, err := excelize.CellNameToCoordinates(to)
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("invalid cell range: %s", cellRange)
+	}
+	return startCol, startRow, endCol, endRow, nil
+}
+
+// writeExcelSheet writes the header and rows of the DataFrame to a sheet
+func (df *DataFrame) writeExcelSheet(f *excelize.File, sheet string) error {
+	header := make([]interface{}, 0, df.numCols)
+	for _, name := range df.columns.Keys() {
+		header = append(header, name)
+	}
+	if err := f.SetSheetRow(sheet, "A1", &header); err != nil {
+		return err
+	}
+
+	for row := 0; row < df.numRows; row++ {
+		cell, _ := excelize.CoordinatesToCellName(1, row+2)
+		values := df.GetRow(row)
+		if err := f.SetSheetRow(sheet, cell, &values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package dataframe
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// writeWorkbook writes a workbook whose sheets hold the given rows, starting
+// at A1, and returns its path
+func writeWorkbook(t *testing.T, sheets map[string][][]interface{}) string {
+	t.Helper()
+	f := excelize.NewFile()
+	defer f.Close()
+	first := true
+	for name, rows := range sheets {
+		if first {
+			f.SetSheetName(f.GetSheetName(0), name)
+			first = false
+		} else if _, err := f.NewSheet(name); err != nil {
+			t.Fatalf("NewSheet: %v", err)
+		}
+		for i, row := range rows {
+			cell, _ := excelize.CoordinatesToCellName(1, i+1)
+			if err := f.SetSheetRow(name, cell, &row); err != nil {
+				t.Fatalf("SetSheetRow: %v", err)
+			}
+		}
+	}
+	path := filepath.Join(t.TempDir(), "book.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("SaveAs: %v", err)
+	}
+	return path
+}
+
+func TestExcelRoundTrip(t *testing.T) {
+	people := newFrame(t,
+		"name", "string", []interface{}{"ann", "bob"},
+		"age", "int", []interface{}{30, nil},
+		"score", "float", []interface{}{1.5, 2.25},
+		"active", "bool", []interface{}{true, false},
+	)
+	totals := newFrame(t, "total", "int", []interface{}{7})
+	path := filepath.Join(t.TempDir(), "out.xlsx")
+	if err := WriteExcel(path, map[string]*DataFrame{"people": people, "totals": totals}); err != nil {
+		t.Fatalf("WriteExcel: %v", err)
+	}
+
+	sheets, err := ReadExcelSheets(path, DefaultExcelReadOptions())
+	if err != nil {
+		t.Fatalf("ReadExcelSheets: %v", err)
+	}
+	if len(sheets) != 2 {
+		t.Fatalf("got %d sheets, want 2", len(sheets))
+	}
+	back := sheets["people"]
+	checkColumn(t, back, "name", "string", []interface{}{"ann", "bob"})
+	checkColumn(t, back, "age", "int", []interface{}{30, nil})
+	checkColumn(t, back, "score", "float", []interface{}{1.5, 2.25})
+	checkColumn(t, back, "active", "bool", []interface{}{true, false})
+	checkColumn(t, sheets["totals"], "total", "int", []interface{}{7})
+
+	single, err := ReadExcel(path, "totals", DefaultExcelReadOptions())
+	if err != nil {
+		t.Fatalf("ReadExcel: %v", err)
+	}
+	checkColumn(t, single, "total", "int", []interface{}{7})
+}
+
+func TestReadExcelOptions(t *testing.T) {
+	path := writeWorkbook(t, map[string][][]interface{}{
+		"data": {
+			{"skip", "skip", "skip"},
+			{"skip", "a", "b"},
+			{"skip", 1, "x"},
+			{"skip", 2, "NA"},
+		},
+	})
+	tests := []struct {
+		name    string
+		options ExcelReadOptions
+		columns []string
+		values  [][]interface{}
+	}{
+		{
+			name:    "range with header",
+			options: ExcelReadOptions{Header: true, Range: "B2:C4", NullValues: []string{"NA"}},
+			columns: []string{"a", "b"},
+			values:  [][]interface{}{{1, 2}, {"x", nil}},
+		},
+		{
+			name:    "range without header",
+			options: ExcelReadOptions{Range: "B3:B4"},
+			columns: []string{"B"},
+			values:  [][]interface{}{{1, 2}},
+		},
+		{
+			name:    "schema",
+			options: ExcelReadOptions{Header: true, Range: "B2:B4", Schema: map[string]string{"a": "float"}},
+			columns: []string{"a"},
+			values:  [][]interface{}{{1.0, 2.0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			df, err := ReadExcel(path, "data", tt.options)
+			if err != nil {
+				t.Fatalf("ReadExcel: %v", err)
+			}
+			if got := df.columns.Keys(); !reflect.DeepEqual(got, tt.columns) {
+				t.Fatalf("columns: got %v, want %v", got, tt.columns)
+			}
+			for i, name := range tt.columns {
+				if got := columnValues(t, df, name); !reflect.DeepEqual(got, tt.values[i]) {
+					t.Errorf("column %s: got %v, want %v", name, got, tt.values[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadExcelBlankHeaders(t *testing.T) {
+	path := writeWorkbook(t, map[string][][]interface{}{
+		"data": {
+			{"a", "", nil},
+			{1, 2, 3},
+		},
+	})
+	df, err := ReadExcel(path, "", DefaultExcelReadOptions())
+	if err != nil {
+		t.Fatalf("ReadExcel: %v", err)
+	}
+	if got := df.columns.Keys(); !reflect.DeepEqual(got, []string{"a", "column_1", "column_2"}) {
+		t.Errorf("columns: got %v", got)
+	}
+}
+
+func TestReadExcelSheetsEmptySheet(t *testing.T) {
+	path := writeWorkbook(t, map[string][][]interface{}{
+		"empty": nil,
+		"data":  {{"a"}, {1}},
+	})
+	sheets, err := ReadExcelSheets(path, DefaultExcelReadOptions())
+	if err != nil {
+		t.Fatalf("ReadExcelSheets: %v", err)
+	}
+	if shape := sheets["empty"].Shape(); shape[0] != 0 || shape[1] != 0 {
+		t.Errorf("empty sheet: got shape %v", shape)
+	}
+	checkColumn(t, sheets["data"], "a", "int", []interface{}{1})
+}
+
+func TestExcelErrors(t *testing.T) {
+	path := writeWorkbook(t, map[string][][]interface{}{"data": {{"a"}, {1}}})
+	tests := []struct {
+		name    string
+		sheet   string
+		options ExcelReadOptions
+		want    string
+	}{
+		{"missing sheet", "nope", DefaultExcelReadOptions(), "sheet 'nope' does not exist"},
+		{"malformed range", "data", ExcelReadOptions{Range: "A1"}, "invalid cell range"},
+		{"reversed range", "data", ExcelReadOptions{Range: "B2:A1"}, "invalid cell range"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadExcel(path, tt.sheet, tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	if err := WriteExcel(filepath.Join(t.TempDir(), "none.xlsx"), nil); err == nil {
+		t.Error("expected an error when writing no sheets")
+	}
+}
@@ -0,0 +1,206 @@
+package dataframe
+
+import (
+	"fmt"
+	"koalas/series"
+	"math"
+	"strings"
+	"time"
+)
+
+// Explode turns every element of a list column into its own row, repeating
+// the values of the other columns. The column takes the datatype of its
+// elements, and a nil or empty list gives a single row holding nil. Every
+// Entry keeps the Index of the row it came from, which Implode uses to undo
+// the explode. As Implode leaves nil values out of its lists, that row comes
+// back as an empty list: an empty list survives the round trip, but a nil
+// list becomes an empty one.
+func (df *DataFrame) Explode(column string) (*DataFrame, error) {
+	col, exists := df.columns.Get(column)
+	if !exists {
+		return nil, fmt.Errorf("column '%s' does not exist in DataFrame", column)
+	}
+	elem, ok := series.ParseListType(col.Datatype)
+	if !ok {
+		return nil, fmt.Errorf("cannot explode column %s of type %s", column, col.Datatype)
+	}
+
+	// Work out the rows every list becomes
+	lists := make([][]interface{}, len(col.Data))
+	rows := 0
+	for i, entry := range col.Data {
+		elems, ok := series.ListElements(entry.Value)
+		if !ok {
+			return nil, fmt.Errorf("column %s, row %d: invalid type: expected %s, got %T", column, i, col.Datatype, entry.Value)
+		}
+		if len(elems) == 0 {
+			elems = []interface{}{nil}
+		}
+		lists[i] = elems
+		rows += len(elems)
+	}
+
+	result := &DataFrame{
+		columns: NewOrderedMap(),
+		numCols: df.numCols,
+		numRows: rows,
+		schema:  make(map[string]string),
+	}
+	for _, name := range df.columns.Keys() {
+		src, _ := df.columns.Get(name)
+		datatype := src.Datatype
+		if name == column {
+			datatype = elem
+		}
+
+		data := make([]series.Entry, 0, rows)
+		for i, entry := range src.Data {
+			for _, v := range lists[i] {
+				if name == column {
+					entry.Value = v
+				}
+				data = append(data, entry)
+			}
+		}
+
+		result.columns.Set(name, &series.Series{
+			Name:     src.Name,
+			Datatype: datatype,
+			Data:     data,
+		})
+		result.schema[name] = datatype
+	}
+
+	return result, nil
+}
+
+// Implode collects the values of a column into lists, the inverse of Explode.
+// Without by, rows are grouped by the Index of their Entry in the column, so
+// the rows Explode made from one row become that row again, and the other
+// columns keep the values of the first row of each group. With by, rows are
+// grouped by equal values in the by columns, and the result only holds the by
+// columns followed by the list column, with rows indexed from 0. Groups keep
+// the order they first appear in and nil values are left out of the lists, so
+// a group holding only nil gives an empty list.
+func (df *DataFrame) Implode(column string, by ...string) (*DataFrame, error) {
+	col, exists := df.columns.Get(column)
+	if !exists {
+		return nil, fmt.Errorf("column '%s' does not exist in DataFrame", column)
+	}
+	listType := series.ListType(col.Datatype)
+	if !series.IsListDatatype(listType) {
+		return nil, fmt.Errorf("cannot implode column %s of type %s", column, col.Datatype)
+	}
+	keyCols := make([]*series.Series, len(by))
+	for i, name := range by {
+		keyCol, exists := df.columns.Get(name)
+		if !exists {
+			return nil, fmt.Errorf("column '%s' does not exist in DataFrame", name)
+		}
+		if name == column {
+			return nil, fmt.Errorf("cannot implode column %s while grouping by it", column)
+		}
+		keyCols[i] = keyCol
+	}
+
+	// Find the groups, each one a list of row positions
+	groupOf := make(map[string]int)
+	var groups [][]int
+	for pos, entry := range col.Data {
+		var key string
+		if len(by) == 0 {
+			key = fmt.Sprint(entry.Index)
+		} else {
+			parts := make([]string, len(keyCols))
+			for i, keyCol := range keyCols {
+				parts[i] = groupKey(keyCol.Data[pos].Value)
+			}
+			key = strings.Join(parts, "\x00")
+		}
+		g, exists := groupOf[key]
+		if !exists {
+			g = len(groups)
+			groupOf[key] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], pos)
+	}
+
+	names := df.columns.Keys()
+	if len(by) > 0 {
+		names = append(by[:len(by):len(by)], column)
+	}
+	result := &DataFrame{
+		columns: NewOrderedMap(),
+		numCols: len(names),
+		numRows: len(groups),
+		schema:  make(map[string]string),
+	}
+	for _, name := range names {
+		src, _ := df.columns.Get(name)
+		datatype := src.Datatype
+		if name == column {
+			datatype = listType
+		}
+
+		data := make([]series.Entry, len(groups))
+		for g, positions := range groups {
+			first := src.Data[positions[0]]
+			data[g].Index = first.Index
+			if len(by) > 0 {
+				data[g].Index = g
+			}
+			if name != column {
+				data[g].Value = first.Value
+				continue
+			}
+
+			values := make([]interface{}, 0, len(positions))
+			for _, pos := range positions {
+				if v := src.Data[pos].Value; v != nil {
+					values = append(values, v)
+				}
+			}
+			list, err := series.NewList(listType, values)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %v", column, err)
+			}
+			data[g].Value = list
+		}
+
+		result.columns.Set(name, &series.Series{
+			Name:     src.Name,
+			Datatype: datatype,
+			Data:     data,
+		})
+		result.schema[name] = datatype
+	}
+
+	return result, nil
+}
+
+// groupKey returns a string that is the same for values Implode groups
+// together. Datetimes are grouped by instant, whatever their location. Floats
+// group -0 with 0, and every NaN with the other NaNs.
+func groupKey(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		value = v.UTC().Format(time.RFC3339Nano)
+	case float64:
+		value = normalizeFloat(v)
+	case float32:
+		value = float32(normalizeFloat(float64(v)))
+	}
+	return fmt.Sprintf("%T:%v", value, value)
+}
+
+// normalizeFloat turns -0 into 0 and every NaN into the same NaN
+func normalizeFloat(f float64) float64 {
+	switch {
+	case f == 0:
+		return 0
+	case math.IsNaN(f):
+		return math.NaN()
+	}
+	return f
+}
@@ -0,0 +1,135 @@
+package dataframe
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExplodeImplode(t *testing.T) {
+	df := newFrame(t,
+		"id", "int", []interface{}{1, 2, 3, 4},
+		"tags", "list<string>", []interface{}{[]string{"a", "b"}, []string{}, nil, []string{"c"}},
+	)
+	exploded, err := df.Explode("tags")
+	if err != nil {
+		t.Fatalf("Explode: %v", err)
+	}
+	checkColumn(t, exploded, "id", "int", []interface{}{1, 1, 2, 3, 4})
+	checkColumn(t, exploded, "tags", "string", []interface{}{"a", "b", nil, nil, "c"})
+
+	// Empty lists come back, nil lists come back empty
+	back, err := exploded.Implode("tags")
+	if err != nil {
+		t.Fatalf("Implode: %v", err)
+	}
+	checkColumn(t, back, "id", "int", []interface{}{1, 2, 3, 4})
+	checkColumn(t, back, "tags", "list<string>", []interface{}{[]string{"a", "b"}, []string{}, []string{}, []string{"c"}})
+}
+
+func TestImplodeBy(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		name   string
+		key    []interface{}
+		dtype  string
+		keys   []interface{}
+		values []interface{}
+	}{
+		{
+			name:   "ints in first appearance order",
+			key:    []interface{}{2, 1, 2, nil, nil},
+			dtype:  "int",
+			keys:   []interface{}{2, 1, nil},
+			values: []interface{}{[]int{10, 30}, []int{20}, []int{40, 50}},
+		},
+		{
+			name:   "negative zero groups with zero",
+			key:    []interface{}{0.0, math.Copysign(0, -1), 1.5, 0.0, 1.5},
+			dtype:  "float",
+			keys:   []interface{}{0.0, 1.5},
+			values: []interface{}{[]int{10, 20, 40}, []int{30, 50}},
+		},
+		{
+			name:   "NaNs group together",
+			key:    []interface{}{nan, 1.0, math.Float64frombits(0x7ff8000000000001), nan, 1.0},
+			dtype:  "float",
+			keys:   []interface{}{"NaN", 1.0},
+			values: []interface{}{[]int{10, 30, 40}, []int{20, 50}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			df := newFrame(t,
+				"k", tt.dtype, tt.key,
+				"v", "int", []interface{}{10, 20, 30, 40, 50},
+			)
+			got, err := df.Implode("v", "k")
+			if err != nil {
+				t.Fatalf("Implode: %v", err)
+			}
+			if names := got.columns.Keys(); !reflect.DeepEqual(names, []string{"k", "v"}) {
+				t.Errorf("columns: got %v", names)
+			}
+			keys := columnValues(t, got, "k")
+			for i, key := range keys {
+				if f, ok := key.(float64); ok && math.IsNaN(f) {
+					keys[i] = "NaN"
+				}
+			}
+			if !reflect.DeepEqual(keys, tt.keys) {
+				t.Errorf("keys: got %v, want %v", keys, tt.keys)
+			}
+			checkColumn(t, got, "v", "list<int>", tt.values)
+		})
+	}
+}
+
+func TestExplodeImplodeErrors(t *testing.T) {
+	df := newFrame(t,
+		"n", "int", []interface{}{1},
+		"l", "list<int>", []interface{}{[]int{1}},
+	)
+	tests := []struct {
+		name  string
+		apply func() error
+		want  string
+	}{
+		{"explode missing", func() error { _, err := df.Explode("x"); return err }, "column 'x' does not exist"},
+		{"explode non-list", func() error { _, err := df.Explode("n"); return err }, "cannot explode column n of type int"},
+		{"implode missing", func() error { _, err := df.Implode("x"); return err }, "column 'x' does not exist"},
+		{"implode by missing", func() error { _, err := df.Implode("n", "x"); return err }, "column 'x' does not exist"},
+		{"implode by itself", func() error { _, err := df.Implode("n", "n"); return err }, "while grouping by it"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.apply()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupKey(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b interface{}
+		same bool
+	}{
+		{"zeros", 0.0, math.Copysign(0, -1), true},
+		{"float32 zeros", float32(0), float32(math.Copysign(0, -1)), true},
+		{"NaNs", math.NaN(), math.Float64frombits(0x7ff8000000000001), true},
+		{"int and float", 1, 1.0, false},
+		{"different floats", 1.0, 2.0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := groupKey(tt.a) == groupKey(tt.b); got != tt.same {
+				t.Errorf("got same %v, want %v", got, tt.same)
+			}
+		})
+	}
+}
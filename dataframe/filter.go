@@ -10,21 +10,68 @@ func (df *DataFrame) Where(column string, value interface{}) (*DataFrame, error)
 	return df.Filter(column, value)
 }
 
+// Filter keeps the rows whose value in the column equals value, as
+// series.Equal compares them. Nil values never match, as in SQL, so rows
+// holding nil are selected with FilterNull instead.
 func (df *DataFrame) Filter(column string, value interface{}) (*DataFrame, error) {
 	// Validate input
 	col, exists := df.columns.Get(column)
 	if !exists {
 		return nil, fmt.Errorf("column '%s' does not exist in DataFrame", column)
 	}
+	if value == nil {
+		return nil, fmt.Errorf("cannot filter column %s on nil, use FilterNull", column)
+	}
+
+	// Look a label up once so that category values are matched by code
+	if label, ok := value.(string); ok && col.Datatype == "category" {
+		if categories, err := col.Cat().Dictionary(); err == nil {
+			if c, ok := categories.Get(label); ok {
+				value = c
+			}
+		}
+	}
 
 	// Find indexes where the value matches
 	indexes := []int{}
 	for i, entry := range col.Data {
-		if entry.Value == value {
+		if series.Equal(entry.Value, value) {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return df.keepRows(indexes), nil
+}
+
+// FilterNull keeps the rows holding nil in the column
+func (df *DataFrame) FilterNull(column string) (*DataFrame, error) {
+	return df.filterValidity(column, false)
+}
+
+// FilterNotNull keeps the rows holding a value in the column
+func (df *DataFrame) FilterNotNull(column string) (*DataFrame, error) {
+	return df.filterValidity(column, true)
+}
+
+// filterValidity keeps the rows whose validity bit in the column equals valid
+func (df *DataFrame) filterValidity(column string, valid bool) (*DataFrame, error) {
+	col, exists := df.columns.Get(column)
+	if !exists {
+		return nil, fmt.Errorf("column '%s' does not exist in DataFrame", column)
+	}
+
+	validity := col.Validity()
+	indexes := []int{}
+	for i := range col.Data {
+		if validity.Get(i) == valid {
 			indexes = append(indexes, i)
 		}
 	}
+	return df.keepRows(indexes), nil
+}
 
+// keepRows keeps only the rows at the given positions, in place
+func (df *DataFrame) keepRows(indexes []int) *DataFrame {
 	// Update each column in place
 	for _, name := range df.columns.Keys() {
 		if col, exists := df.columns.Get(name); exists {
@@ -39,5 +86,5 @@ func (df *DataFrame) Filter(column string, value interface{}) (*DataFrame, error
 
 	// Update row count
 	df.numRows = len(indexes)
-	return df, nil
+	return df
 }
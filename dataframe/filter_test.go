@@ -0,0 +1,40 @@
+package dataframe
+
+import (
+	"koalas/series"
+	"testing"
+)
+
+func TestFilterCategory(t *testing.T) {
+	// Filters keep rows in place, so each case builds its own frame
+	frame := func() *DataFrame {
+		df, err := Create([]*series.Series{
+			newCategory(t, "k", []interface{}{"x", "y", nil, "x"}),
+			newSeries("n", "int", []interface{}{1, 2, 3, 4}),
+		})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		return df
+	}
+
+	tests := []struct {
+		name   string
+		filter func(df *DataFrame) (*DataFrame, error)
+		want   []interface{}
+	}{
+		{"label", func(df *DataFrame) (*DataFrame, error) { return df.Filter("k", "x") }, []interface{}{1, 4}},
+		{"missing label", func(df *DataFrame) (*DataFrame, error) { return df.Filter("k", "z") }, []interface{}{}},
+		{"null", func(df *DataFrame) (*DataFrame, error) { return df.FilterNull("k") }, []interface{}{3}},
+		{"not null", func(df *DataFrame) (*DataFrame, error) { return df.FilterNotNull("k") }, []interface{}{1, 2, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.filter(frame())
+			if err != nil {
+				t.Fatalf("got error %v", err)
+			}
+			checkColumn(t, got, "n", "int", tt.want)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package dataframe
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadFixedWidth(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		options FixedWidthReadOptions
+		columns []string
+		values  [][]interface{}
+	}{
+		{
+			name:    "detected columns with header",
+			input:   "id name  score\n 1 ann    1.5\n\n 2 bob       \n",
+			options: DefaultFixedWidthReadOptions(),
+			columns: []string{"id", "name", "score"},
+			values:  [][]interface{}{{1, 2}, {"ann", "bob"}, {1.5, nil}},
+		},
+		{
+			name:  "explicit specs",
+			input: "00042XYZtrue\n00007AB false\n",
+			options: FixedWidthReadOptions{
+				Columns: []FixedWidthColumn{
+					{Name: "n", Start: 0, Width: 5},
+					{Name: "code", Start: 5, Width: 3},
+					{Name: "ok", Start: 8, Width: 5, Datatype: "bool"},
+				},
+			},
+			columns: []string{"n", "code", "ok"},
+			values:  [][]interface{}{{42, 7}, {"XYZ", "AB"}, {true, false}},
+		},
+		{
+			name:  "names from the header and a schema",
+			input: "a   b\n1   2\n",
+			options: FixedWidthReadOptions{
+				Header: true,
+				Columns: []FixedWidthColumn{
+					{Start: 0, Width: 4, Datatype: "float"},
+					{Name: "renamed", Start: 4, Width: 1},
+				},
+			},
+			columns: []string{"a", "renamed"},
+			values:  [][]interface{}{{1.0}, {2}},
+		},
+		{
+			name:    "without header",
+			input:   "x 1\ny 2\n",
+			options: FixedWidthReadOptions{NullValues: []string{"-"}},
+			columns: []string{"column_0", "column_1"},
+			values:  [][]interface{}{{"x", "y"}, {1, 2}},
+		},
+		{
+			name:    "short lines and null values",
+			input:   "a  b\n1  -\n2\n",
+			options: FixedWidthReadOptions{Header: true, NullValues: []string{"", "-"}},
+			columns: []string{"a", "b"},
+			values:  [][]interface{}{{1, 2}, {nil, nil}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			df, err := ReadFixedWidth(strings.NewReader(tt.input), tt.options)
+			if err != nil {
+				t.Fatalf("ReadFixedWidth: %v", err)
+			}
+			if got := df.columns.Keys(); !reflect.DeepEqual(got, tt.columns) {
+				t.Fatalf("columns: got %v, want %v", got, tt.columns)
+			}
+			for i, name := range tt.columns {
+				if got := columnValues(t, df, name); !reflect.DeepEqual(got, tt.values[i]) {
+					t.Errorf("column %s: got %v, want %v", name, got, tt.values[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadFixedWidthErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		options FixedWidthReadOptions
+		want    string
+	}{
+		{"empty", "\n  \n", DefaultFixedWidthReadOptions(), "fixed-width input is empty"},
+		{"negative start", "abc\n", FixedWidthReadOptions{Columns: []FixedWidthColumn{{Name: "a", Start: -1, Width: 2}}}, "invalid column spec for a"},
+		{"zero width", "abc\n", FixedWidthReadOptions{Columns: []FixedWidthColumn{{Name: "a", Width: 0}}}, "invalid column spec for a"},
+		{"schema mismatch", "a\nx\n", FixedWidthReadOptions{Header: true, Columns: []FixedWidthColumn{{Width: 1, Datatype: "int"}}}, "column a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadFixedWidth(strings.NewReader(tt.input), tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteFixedWidth(t *testing.T) {
+	df := newFrame(t,
+		"name", "string", []interface{}{"ann", nil},
+		"n", "int", []interface{}{1, 200},
+	)
+	var buf bytes.Buffer
+	if err := df.WriteFixedWidth(&buf, true); err != nil {
+		t.Fatalf("WriteFixedWidth: %v", err)
+	}
+	want := "name n  \nann  1  \n     200\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// What is written reads back with detected columns
+	back, err := ReadFixedWidth(&buf, DefaultFixedWidthReadOptions())
+	if err != nil {
+		t.Fatalf("ReadFixedWidth: %v", err)
+	}
+	checkColumn(t, back, "name", "string", []interface{}{"ann", nil})
+	checkColumn(t, back, "n", "int", []interface{}{1, 200})
+
+	buf.Reset()
+	if err := df.WriteFixedWidth(&buf, false); err != nil {
+		t.Fatalf("WriteFixedWidth: %v", err)
+	}
+	if got := buf.String(); got != "ann  1  \n     200\n" {
+		t.Errorf("without header: got %q", got)
+	}
+}
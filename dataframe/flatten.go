@@ -0,0 +1,108 @@
+package dataframe
+
+import (
+	"fmt"
+	"koalas/series"
+)
+
+// FlattenOptions configures how Flatten names and expands struct fields
+type FlattenOptions struct {
+	Separator string            // Text between the column name and the field name
+	Recursive bool              // Also flatten fields that hold structs
+	Schema    map[string]string // Datatypes of flattened columns by name, others are inferred
+}
+
+// DefaultFlattenOptions returns the default options for Flatten
+func DefaultFlattenOptions() FlattenOptions {
+	return FlattenOptions{
+		Separator: ".",
+		Recursive: true,
+	}
+}
+
+// Flatten replaces a struct column with one column per field found in any of
+// its values, named after the column and the field, such as "user.name". The
+// datatype of each field comes from the schema or is inferred from its values,
+// and records missing a field hold nil. A field holding only nil is a string
+// column, which Union treats as untyped. Fields are sorted by name and take
+// the place of the column. With Recursive set, fields holding structs are
+// flattened in turn. A name that another column already has is made unique
+// with a suffix, as in "user.name_1".
+func (df *DataFrame) Flatten(column string, options FlattenOptions) (*DataFrame, error) {
+	col, exists := df.columns.Get(column)
+	if !exists {
+		return nil, fmt.Errorf("column '%s' does not exist in DataFrame", column)
+	}
+	if col.Datatype != "struct" {
+		return nil, fmt.Errorf("cannot flatten column %s of type %s", column, col.Datatype)
+	}
+	fields, err := flattenStruct(col, column, options)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DataFrame{
+		columns: NewOrderedMap(),
+		numRows: df.numRows,
+		schema:  make(map[string]string),
+	}
+	for _, name := range df.columns.Keys() {
+		if name != column {
+			src, _ := df.columns.Get(name)
+			result.columns.Set(name, src)
+			result.schema[name] = src.Datatype
+			continue
+		}
+		for _, field := range fields {
+			field.Name = uniqueColumnName(field.Name, func(name string) bool {
+				_, taken := result.columns.Get(name)
+				_, exists := df.columns.Get(name)
+				return taken || (exists && name != column)
+			})
+			result.columns.Set(field.Name, field)
+			result.schema[field.Name] = field.Datatype
+		}
+	}
+	result.numCols = result.columns.Len()
+
+	return result, nil
+}
+
+// flattenStruct builds a Series for every field of a struct Series, named
+// with the given prefix
+func flattenStruct(col *series.Series, prefix string, options FlattenOptions) ([]*series.Series, error) {
+	names, err := col.Struct().Fields()
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []*series.Series
+	for _, name := range names {
+		fieldName := prefix + options.Separator + name
+		field, err := col.Struct().Field(name, options.Schema[fieldName])
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %v", prefix, err)
+		}
+		field.Name = fieldName
+		if options.Recursive && field.Datatype == "struct" {
+			nested, err := flattenStruct(field, field.Name, options)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, nested...)
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// uniqueColumnName returns name, or name followed by the first suffix "_1",
+// "_2" and so on that taken reports as free
+func uniqueColumnName(name string, taken func(name string) bool) string {
+	unique := name
+	for i := 1; taken(unique); i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	return unique
+}
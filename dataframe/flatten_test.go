@@ -0,0 +1,173 @@
+package dataframe
+
+import (
+	"koalas/series"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// jsonStructs decodes JSON objects into struct values, turning "null" into nil
+func jsonStructs(t *testing.T, texts ...string) []interface{} {
+	t.Helper()
+	values := make([]interface{}, len(texts))
+	for i, text := range texts {
+		raw, err := series.DecodeJSONValue([]byte(text))
+		if err != nil {
+			t.Fatalf("DecodeJSONValue: %v", err)
+		}
+		values[i] = raw
+	}
+	return values
+}
+
+func TestFlatten(t *testing.T) {
+	users := jsonStructs(t,
+		`{"name":"ann","address":{"city":"Oslo","zip":"0150"},"note":null}`,
+		`{"name":"bob","age":30,"address":null}`,
+		`null`,
+	)
+	tests := []struct {
+		name    string
+		options FlattenOptions
+		columns []string
+		check   func(t *testing.T, df *DataFrame)
+	}{
+		{
+			name:    "recursive",
+			options: DefaultFlattenOptions(),
+			columns: []string{"id", "user.address.city", "user.address.zip", "user.age", "user.name", "user.note", "tail"},
+			check: func(t *testing.T, df *DataFrame) {
+				checkColumn(t, df, "user.address.city", "string", []interface{}{"Oslo", nil, nil})
+				checkColumn(t, df, "user.age", "int", []interface{}{nil, 30, nil})
+				checkColumn(t, df, "user.name", "string", []interface{}{"ann", "bob", nil})
+				checkColumn(t, df, "user.note", "string", []interface{}{nil, nil, nil})
+			},
+		},
+		{
+			name:    "one level with separator",
+			options: FlattenOptions{Separator: "_"},
+			columns: []string{"id", "user_address", "user_age", "user_name", "user_note", "tail"},
+			check: func(t *testing.T, df *DataFrame) {
+				col, _ := df.columns.Get("user_address")
+				if col.Datatype != "struct" {
+					t.Errorf("user_address: got datatype %s, want struct", col.Datatype)
+				}
+			},
+		},
+		{
+			name:    "schema",
+			options: FlattenOptions{Separator: ".", Recursive: true, Schema: map[string]string{"user.note": "int", "user.age": "float"}},
+			columns: []string{"id", "user.address.city", "user.address.zip", "user.age", "user.name", "user.note", "tail"},
+			check: func(t *testing.T, df *DataFrame) {
+				checkColumn(t, df, "user.note", "int", []interface{}{nil, nil, nil})
+				checkColumn(t, df, "user.age", "float", []interface{}{nil, 30.0, nil})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			df := newFrame(t,
+				"id", "int", []interface{}{1, 2, 3},
+				"user", "struct", users,
+				"tail", "bool", []interface{}{true, false, true},
+			)
+			got, err := df.Flatten("user", tt.options)
+			if err != nil {
+				t.Fatalf("Flatten: %v", err)
+			}
+			if names := got.columns.Keys(); !reflect.DeepEqual(names, tt.columns) {
+				t.Errorf("columns: got %v, want %v", names, tt.columns)
+			}
+			if shape := got.Shape(); shape[0] != 3 || shape[1] != len(tt.columns) {
+				t.Errorf("got shape %v", shape)
+			}
+			tt.check(t, got)
+		})
+	}
+}
+
+func TestFlattenCollisions(t *testing.T) {
+	df := newFrame(t,
+		"user.name", "string", []interface{}{"before"},
+		"user", "struct", jsonStructs(t, `{"name":"ann","id":1}`),
+		"user.id", "string", []interface{}{"after"},
+	)
+	got, err := df.Flatten("user", DefaultFlattenOptions())
+	if err != nil {
+		t.Fatalf("Flatten: %v", err)
+	}
+	want := []string{"user.name", "user.id_1", "user.name_1", "user.id"}
+	if names := got.columns.Keys(); !reflect.DeepEqual(names, want) {
+		t.Errorf("columns: got %v, want %v", names, want)
+	}
+	checkColumn(t, got, "user.name", "string", []interface{}{"before"})
+	checkColumn(t, got, "user.name_1", "string", []interface{}{"ann"})
+	checkColumn(t, got, "user.id_1", "int", []interface{}{1})
+	checkColumn(t, got, "user.id", "string", []interface{}{"after"})
+}
+
+func TestFlattenNullFieldUnions(t *testing.T) {
+	empty := newFrame(t, "u", "struct", jsonStructs(t, `{"n":null}`))
+	typed := newFrame(t, "u", "struct", jsonStructs(t, `{"n":5}`))
+	a, err := empty.Flatten("u", DefaultFlattenOptions())
+	if err != nil {
+		t.Fatalf("Flatten: %v", err)
+	}
+	b, err := typed.Flatten("u", DefaultFlattenOptions())
+	if err != nil {
+		t.Fatalf("Flatten: %v", err)
+	}
+	combined, err := a.Union(b)
+	if err != nil {
+		t.Fatalf("Union: %v", err)
+	}
+	checkColumn(t, combined, "u.n", "int", []interface{}{nil, 5})
+}
+
+func TestFlattenGoLiterals(t *testing.T) {
+	users, err := series.Create("u", "struct", []interface{}{
+		map[string]interface{}{"id": 1, "score": 0.5, "address": map[string]interface{}{"floor": uint16(3)}},
+		map[string]interface{}{"id": int32(2), "score": 2},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	df, err := Create([]*series.Series{users})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	flat, err := df.Flatten("u", DefaultFlattenOptions())
+	if err != nil {
+		t.Fatalf("Flatten: %v", err)
+	}
+	checkColumn(t, flat, "u.id", "int", []interface{}{1, 2})
+	checkColumn(t, flat, "u.score", "float", []interface{}{0.5, 2.0})
+	checkColumn(t, flat, "u.address.floor", "int", []interface{}{3, nil})
+}
+
+func TestFlattenErrors(t *testing.T) {
+	df := newFrame(t,
+		"n", "int", []interface{}{1},
+		"u", "struct", jsonStructs(t, `{"a":"x"}`),
+	)
+	tests := []struct {
+		name    string
+		column  string
+		options FlattenOptions
+		want    string
+	}{
+		{"missing column", "x", DefaultFlattenOptions(), "column 'x' does not exist"},
+		{"not a struct", "n", DefaultFlattenOptions(), "cannot flatten column n of type int"},
+		{"schema mismatch", "u", FlattenOptions{Separator: ".", Schema: map[string]string{"u.a": "int"}}, "column u: a at position 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := df.Flatten(tt.column, tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
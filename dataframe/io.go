@@ -1 +1,857 @@
 package dataframe
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"koalas/series"
+	"koalas/utils"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dsnet/compress/bzip2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// ReaderAtSeeker is the random access input that Parquet and Arrow files are
+// read from, satisfied by *os.File and *bytes.Reader
+type ReaderAtSeeker interface {
+	io.Reader
+	io.ReaderAt
+	io.Seeker
+}
+
+// CSVReadOptions configures how ReadCSV parses its input
+type CSVReadOptions struct {
+	Delimiter        rune              // Field delimiter, defaults to ','
+	Comment          rune              // Lines starting with this rune are skipped, 0 disables
+	Header           bool              // Whether the first record holds the column names
+	NullValues       []string          // Tokens that are read as nil
+	Schema           map[string]string // Explicit datatypes that override inference
+	TrimLeadingSpace bool              // Ignore leading white space in a field
+	LazyQuotes       bool              // Allow quotes to appear in unquoted fields
+	DatetimeLayouts  []string          // Layouts tried for datetime columns, setting them also infers datetime columns
+}
+
+// DefaultCSVReadOptions returns the options used for a typical CSV file
+func DefaultCSVReadOptions() CSVReadOptions {
+	return CSVReadOptions{
+		Delimiter:  ',',
+		Header:     true,
+		NullValues: []string{"", "NA", "null", "NULL"},
+	}
+}
+
+// ReadCSV reads a CSV file into a new DataFrame, inferring the datatype of each column.
+// Input compressed with gzip, zstd, bzip2 or xz is decompressed.
+func ReadCSV(r io.Reader, options CSVReadOptions) (*DataFrame, error) {
+	// Compressed input is detected from its first bytes
+	dr, err := decompress(r)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing csv: %v", err)
+	}
+	defer dr.Close()
+
+	reader := csv.NewReader(dr)
+	if options.Delimiter != 0 {
+		reader.Comma = options.Delimiter
+	}
+	reader.Comment = options.Comment
+	reader.TrimLeadingSpace = options.TrimLeadingSpace
+	reader.LazyQuotes = options.LazyQuotes
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error reading csv: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("csv input is empty")
+	}
+
+	// Work out the column names
+	var names []string
+	if options.Header {
+		names = records[0]
+		records = records[1:]
+	} else {
+		names = make([]string, len(records[0]))
+		for i := range names {
+			names[i] = fmt.Sprintf("column_%d", i)
+		}
+	}
+
+	// Transpose the records into raw string columns
+	raw := make([][]string, len(names))
+	for i := range raw {
+		raw[i] = make([]string, len(records))
+	}
+	for i, record := range records {
+		for j, field := range record {
+			raw[j][i] = field
+		}
+	}
+
+	return fromStrings(names, raw, options.NullValues, options.Schema, options.DatetimeLayouts)
+}
+
+// fromStrings builds a DataFrame from raw string columns, parsing each value
+// into either the datatype given in schema or the inferred datatype. Datetime
+// columns are only inferred when layouts are given.
+func fromStrings(names []string, raw [][]string, nullValues []string, schema map[string]string, layouts []string) (*DataFrame, error) {
+	for name, datatype := range schema {
+		if !series.IsValidDatatype(datatype) {
+			return nil, fmt.Errorf("invalid type for column %s: %s", name, datatype)
+		}
+	}
+
+	seriesList := make([]*series.Series, len(names))
+	for i, name := range names {
+		datatype, exists := schema[name]
+		if !exists {
+			datatype = inferType(raw[i], nullValues, layouts)
+		}
+
+		values := make([]interface{}, len(raw[i]))
+		for j, field := range raw[i] {
+			if utils.StringContains(nullValues, field) {
+				continue
+			}
+			value, err := parseValue(field, readDatatype(datatype), layouts)
+			if err != nil {
+				return nil, fmt.Errorf("column %s, row %d: %v", name, j, err)
+			}
+			values[j] = value
+		}
+		col, err := newColumnSeries(name, datatype, values)
+		if err != nil {
+			return nil, err
+		}
+		seriesList[i] = col
+	}
+
+	return Create(seriesList)
+}
+
+// newSeries builds a Series from values that are already of the given datatype.
+// Unlike series.Create it accepts an empty slice of values.
+func newSeries(name string, datatype string, values []interface{}) *series.Series {
+	data := make([]series.Entry, len(values))
+	for i, v := range values {
+		data[i] = series.Entry{
+			Value: v,
+			Index: i,
+		}
+	}
+	return &series.Series{
+		Name:     name,
+		Datatype: datatype,
+		Data:     data,
+	}
+}
+
+// newColumnSeries builds the Series of a column read from a file. Category
+// columns are read as strings, see readDatatype, and encoded once every value
+// is known.
+func newColumnSeries(name string, datatype string, values []interface{}) (*series.Series, error) {
+	if datatype == "category" {
+		return newSeries(name, "string", values).AsCategory()
+	}
+	return newSeries(name, datatype, values), nil
+}
+
+// readDatatype returns the datatype the values of a column are read as
+func readDatatype(datatype string) string {
+	if datatype == "category" {
+		return "string"
+	}
+	return datatype
+}
+
+// inferType returns the narrowest datatype that every non-null field can be
+// parsed as. Datetime is only considered when layouts are given.
+func inferType(fields []string, nullValues []string, layouts []string) string {
+	isInt, isFloat, isBool, isDatetime := true, true, true, len(layouts) > 0
+	seen := false
+	for _, field := range fields {
+		if utils.StringContains(nullValues, field) {
+			continue
+		}
+		seen = true
+		if isInt {
+			if _, err := strconv.Atoi(field); err != nil {
+				isInt = false
+			}
+		}
+		if isFloat {
+			if _, err := strconv.ParseFloat(field, 64); err != nil {
+				isFloat = false
+			}
+		}
+		if isBool {
+			if _, err := series.ParseBool(field); err != nil {
+				isBool = false
+			}
+		}
+		if isDatetime {
+			if _, err := series.ParseDatetime(field, layouts); err != nil {
+				isDatetime = false
+			}
+		}
+		if !isInt && !isFloat && !isBool && !isDatetime {
+			return "string"
+		}
+	}
+
+	switch {
+	case !seen:
+		return "string"
+	case isInt:
+		return "int"
+	case isFloat:
+		return "float"
+	case isBool:
+		return "bool"
+	case isDatetime:
+		return "datetime"
+	}
+	return "string"
+}
+
+// parseValue converts a single field into a value of the given datatype,
+// parsing datetimes with the given layouts or the default ones. Decimals are
+// parsed exactly and must fit the precision and scale of their datatype,
+// lists are parsed from JSON arrays and structs from JSON objects.
+func parseValue(field string, datatype string, layouts []string) (interface{}, error) {
+	if series.IsDecimalDatatype(datatype) {
+		d, err := series.ParseDecimal(field)
+		if err != nil {
+			return nil, err
+		}
+		return series.ConvertValue(d, datatype)
+	}
+	if series.IsListDatatype(datatype) || datatype == "struct" {
+		raw, err := series.DecodeJSONValue([]byte(field))
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %q as %s", field, datatype)
+		}
+		return series.FromJSONValue(raw, datatype)
+	}
+
+	switch datatype {
+	case "int":
+		v, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %q as int", field)
+		}
+		return v, nil
+	case "float":
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %q as float", field)
+		}
+		return v, nil
+	case "bool":
+		return series.ParseBool(field)
+	case "string":
+		return field, nil
+	case "datetime":
+		return series.ParseDatetime(field, layouts)
+	case "duration":
+		return series.ParseDuration(field)
+	case "int8", "int16", "int32", "int64":
+		v, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %q as %s", field, datatype)
+		}
+		return series.ConvertValue(v, datatype)
+	case "uint8", "uint16", "uint32", "uint64":
+		v, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %q as %s", field, datatype)
+		}
+		return series.ConvertValue(v, datatype)
+	case "float32":
+		v, err := strconv.ParseFloat(field, 32)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %q as float32", field)
+		}
+		return float32(v), nil
+	}
+	return nil, fmt.Errorf("invalid type: %s", datatype)
+}
+
+// QuotePolicy controls which fields WriteCSV wraps in quotes
+type QuotePolicy int
+
+const (
+	QuoteMinimal    QuotePolicy = iota // Quote only fields that need it
+	QuoteAll                           // Quote every field
+	QuoteNonNumeric                    // Quote every field that is not an int or float
+	QuoteNone                          // Never quote fields
+)
+
+// CSVWriteOptions configures how WriteCSV formats its output
+type CSVWriteOptions struct {
+	Delimiter      rune        // Field delimiter, defaults to ','
+	Quoting        QuotePolicy // When to quote fields
+	Header         bool        // Whether to write the column names first
+	NullValue      string      // Written in place of nil values
+	FloatFormat    byte        // Format passed to strconv.FormatFloat, defaults to 'g'
+	FloatPrecision int         // Precision passed to strconv.FormatFloat, 0 or -1 for the shortest exact value
+	ZeroPrecision  bool        // Pass a precision of 0 to strconv.FormatFloat, writing 3.14 as "3" with FloatFormat 'f'
+	UseCRLF        bool        // End lines with \r\n instead of \n
+	DatetimeLayout string      // Layout used to format datetimes, defaults to time.RFC3339Nano
+}
+
+// DefaultCSVWriteOptions returns the options used to write a typical CSV file
+func DefaultCSVWriteOptions() CSVWriteOptions {
+	return CSVWriteOptions{
+		Delimiter:      ',',
+		Quoting:        QuoteMinimal,
+		Header:         true,
+		FloatFormat:    'g',
+		FloatPrecision: -1,
+		DatetimeLayout: time.RFC3339Nano,
+	}
+}
+
+// WriteCSV writes the DataFrame as CSV, with columns in their current order
+func (df *DataFrame) WriteCSV(w io.Writer, options CSVWriteOptions) error {
+	if options.Delimiter == 0 {
+		options.Delimiter = ','
+	}
+	if options.FloatFormat == 0 {
+		options.FloatFormat = 'g'
+	}
+	// A zero precision would silently drop every fractional digit, so it has
+	// to be asked for with ZeroPrecision
+	if options.ZeroPrecision {
+		options.FloatPrecision = 0
+	} else if options.FloatPrecision == 0 {
+		options.FloatPrecision = -1
+	}
+	if options.DatetimeLayout == "" {
+		options.DatetimeLayout = time.RFC3339Nano
+	}
+	if options.Delimiter == '"' || options.Delimiter == '\r' || options.Delimiter == '\n' {
+		return fmt.Errorf("invalid csv delimiter: %q", options.Delimiter)
+	}
+
+	bw := bufio.NewWriter(w)
+	colNames := df.columns.Keys()
+
+	// Write the header row
+	if options.Header {
+		fields := make([]string, len(colNames))
+		quoted := make([]bool, len(colNames))
+		for i, name := range colNames {
+			fields[i] = name
+			quoted[i] = options.Quoting == QuoteAll || options.Quoting == QuoteNonNumeric
+		}
+		if err := writeCSVRecord(bw, fields, quoted, options); err != nil {
+			return err
+		}
+	}
+
+	// Write the data rows
+	fields := make([]string, len(colNames))
+	quoted := make([]bool, len(colNames))
+	for row := 0; row < df.numRows; row++ {
+		for i, name := range colNames {
+			col, _ := df.columns.Get(name)
+			val, err := col.Get(row)
+			if err != nil {
+				return err
+			}
+			if val == nil {
+				fields[i] = options.NullValue
+				quoted[i] = options.Quoting == QuoteAll
+				continue
+			}
+			fields[i] = formatCSVValue(val, options)
+			switch options.Quoting {
+			case QuoteAll:
+				quoted[i] = true
+			case QuoteNonNumeric:
+				quoted[i] = !series.IsNumericDatatype(col.Datatype) && !series.IsDecimalDatatype(col.Datatype)
+			default:
+				quoted[i] = false
+			}
+		}
+		if err := writeCSVRecord(bw, fields, quoted, options); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// formatCSVValue converts a single non-nil value into its CSV text
+func formatCSVValue(val interface{}, options CSVWriteOptions) string {
+	switch v := val.(type) {
+	case float64:
+		return strconv.FormatFloat(v, options.FloatFormat, options.FloatPrecision, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), options.FloatFormat, options.FloatPrecision, 32)
+	case string:
+		return v
+	case time.Time:
+		return v.Format(options.DatetimeLayout)
+	default:
+		// Lists and structs are written as JSON so that they can be read back
+		_, isList := series.ListElements(v)
+		_, isStruct := v.(map[string]interface{})
+		if (isList && v != nil) || isStruct {
+			if raw, err := json.Marshal(v); err == nil {
+				return string(raw)
+			}
+		}
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// writeCSVRecord writes one line, quoting a field when forced or when it
+// contains characters that would otherwise break the record
+func writeCSVRecord(w *bufio.Writer, fields []string, forceQuote []bool, options CSVWriteOptions) error {
+	for i, field := range fields {
+		if i > 0 {
+			if _, err := w.WriteRune(options.Delimiter); err != nil {
+				return err
+			}
+		}
+
+		quote := forceQuote[i]
+		if options.Quoting == QuoteNone {
+			quote = false
+		} else if !quote {
+			quote = fieldNeedsQuotes(field, options.Delimiter)
+		}
+
+		if !quote {
+			if _, err := w.WriteString(field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Escape embedded quotes by doubling them, as in RFC 4180
+		if _, err := w.WriteString(`"` + strings.ReplaceAll(field, `"`, `""`) + `"`); err != nil {
+			return err
+		}
+	}
+
+	lineEnd := "\n"
+	if options.UseCRLF {
+		lineEnd = "\r\n"
+	}
+	_, err := w.WriteString(lineEnd)
+	return err
+}
+
+// fieldNeedsQuotes reports whether a field must be quoted to be read back unchanged
+func fieldNeedsQuotes(field string, delimiter rune) bool {
+	if field == "" {
+		return false
+	}
+	if field[0] == ' ' || field[0] == '\t' {
+		return true
+	}
+	return strings.ContainsRune(field, delimiter) || strings.ContainsAny(field, "\"\r\n")
+}
+
+// Format identifies a file format for ReadFile and WriteFile
+type Format string
+
+const (
+	FormatCSV         Format = "csv"
+	FormatJSON        Format = "json"
+	FormatNDJSON      Format = "ndjson"
+	FormatParquet     Format = "parquet"
+	FormatArrow       Format = "arrow"  // Arrow IPC file format
+	FormatArrowStream Format = "arrows" // Arrow IPC stream format
+	FormatExcel       Format = "xlsx"
+	FormatKoalas      Format = "koalas" // Native columnar format, see WriteKoalas
+	FormatFixedWidth  Format = "fwf"
+)
+
+// ReadOptions holds the options for every format ReadFile supports
+type ReadOptions struct {
+	CSV        CSVReadOptions
+	JSONOrient JSONOrient        // Orientation of JSON files, empty detects it
+	JSONSchema map[string]string // Explicit datatypes for JSON and NDJSON files
+	Parquet    ParquetReadOptions
+	Excel      ExcelReadOptions
+	Sheet      string // Excel sheet to read, empty reads the first sheet
+	FixedWidth FixedWidthReadOptions
+
+	JSONDatetimeLayouts []string // Datetime layouts for JSON and NDJSON files, setting them also infers datetime columns
+}
+
+// DefaultReadOptions returns the default options of every format
+func DefaultReadOptions() ReadOptions {
+	return ReadOptions{
+		CSV:        DefaultCSVReadOptions(),
+		Excel:      DefaultExcelReadOptions(),
+		FixedWidth: DefaultFixedWidthReadOptions(),
+	}
+}
+
+// WriteOptions holds the options for every format WriteFile supports
+type WriteOptions struct {
+	CSV        CSVWriteOptions
+	JSONOrient JSONOrient // Orientation of JSON files, defaults to split
+	Parquet    ParquetWriteOptions
+	Arrow      ArrowWriteOptions
+	Sheet      string // Excel sheet to write, defaults to Sheet1
+}
+
+// DefaultWriteOptions returns the default options of every format
+func DefaultWriteOptions() WriteOptions {
+	return WriteOptions{
+		CSV:        DefaultCSVWriteOptions(),
+		JSONOrient: JSONSplit,
+		Parquet:    DefaultParquetWriteOptions(),
+		Sheet:      "Sheet1",
+	}
+}
+
+// ReadFile reads a file in the given format into a new DataFrame. An empty
+// format is taken from the file extension. Files compressed with gzip, zstd,
+// bzip2 or xz are detected from their first bytes and decompressed.
+// Uncompressed koalas files are memory mapped, see OpenKoalas.
+func ReadFile(path string, format Format, options ReadOptions) (*DataFrame, error) {
+	if format == "" {
+		detected, err := formatFromPath(path)
+		if err != nil {
+			return nil, err
+		}
+		format = detected
+	}
+	if !isValidFormat(format) {
+		return nil, fmt.Errorf("invalid file format: %s", format)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// The streaming readers decompress their input themselves
+	switch format {
+	case FormatCSV:
+		return ReadCSV(file, options.CSV)
+	case FormatNDJSON:
+		ndjsonOptions := NDJSONReadOptions{
+			Schema:          options.JSONSchema,
+			DatetimeLayouts: options.JSONDatetimeLayouts,
+		}
+		return readNDJSONFrame(file, ndjsonOptions)
+	case FormatArrowStream:
+		return ReadArrowStream(file)
+	case FormatFixedWidth:
+		return ReadFixedWidth(file, options.FixedWidth)
+	case FormatKoalas:
+		// Uncompressed koalas files are memory mapped instead of buffered
+		head := make([]byte, len(koalasMagic))
+		if _, err := io.ReadFull(file, head); err == nil && bytes.Equal(head, koalasMagic) {
+			return readKoalasFile(path)
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("error reading %s: %v", path, err)
+		}
+	}
+
+	r, err := decompress(file)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing %s: %v", path, err)
+	}
+	defer r.Close()
+
+	// The remaining formats need the whole file, so buffer the decompressed data
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", path, err)
+	}
+	switch format {
+	case FormatJSON:
+		return ReadJSON(b, options.JSONOrient, options.JSONSchema, options.JSONDatetimeLayouts)
+	case FormatParquet:
+		return ReadParquet(bytes.NewReader(b), options.Parquet)
+	case FormatArrow:
+		return ReadArrowFile(bytes.NewReader(b))
+	case FormatExcel:
+		return readExcel(bytes.NewReader(b), options.Sheet, options.Excel)
+	case FormatKoalas:
+		return ReadKoalas(bytes.NewReader(b))
+	}
+	return nil, fmt.Errorf("invalid file format: %s", format)
+}
+
+// WriteFile writes the DataFrame to a file in the given format. An empty
+// format is taken from the file extension. A .gz, .zst, .bz2 or .xz extension
+// compresses the output.
+func (df *DataFrame) WriteFile(path string, format Format, options WriteOptions) error {
+	if format == "" {
+		detected, err := formatFromPath(path)
+		if err != nil {
+			return err
+		}
+		format = detected
+	}
+	if !isValidFormat(format) {
+		return fmt.Errorf("invalid file format: %s", format)
+	}
+
+	w, err := createFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case FormatCSV:
+		err = df.WriteCSV(w, options.CSV)
+	case FormatJSON:
+		orient := options.JSONOrient
+		if orient == "" {
+			orient = JSONSplit
+		}
+		var b []byte
+		if b, err = df.ToJSON(orient); err == nil {
+			_, err = w.Write(b)
+		}
+	case FormatNDJSON:
+		err = df.WriteNDJSON(w)
+	case FormatParquet:
+		err = df.WriteParquet(w, options.Parquet)
+	case FormatArrow:
+		err = df.WriteArrowFile(w, options.Arrow)
+	case FormatArrowStream:
+		err = df.WriteArrowStream(w, options.Arrow)
+	case FormatExcel:
+		sheet := options.Sheet
+		if sheet == "" {
+			sheet = "Sheet1"
+		}
+		err = writeExcel(w, map[string]*DataFrame{sheet: df})
+	case FormatKoalas:
+		err = df.WriteKoalas(w)
+	case FormatFixedWidth:
+		err = df.WriteFixedWidth(w, true)
+	}
+
+	// Closing flushes the compressor, so its error matters as much as the write
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// isValidFormat reports whether ReadFile and WriteFile support a format
+func isValidFormat(format Format) bool {
+	switch format {
+	case FormatCSV, FormatJSON, FormatNDJSON, FormatParquet, FormatArrow, FormatArrowStream,
+		FormatExcel, FormatKoalas, FormatFixedWidth:
+		return true
+	}
+	return false
+}
+
+// formatFromPath works out the file format from the extension, ignoring any
+// compression extension
+func formatFromPath(path string) (Format, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if compressionFromExt(ext) != "" {
+		ext = strings.ToLower(filepath.Ext(strings.TrimSuffix(path, filepath.Ext(path))))
+	}
+
+	switch ext {
+	case ".csv", ".txt":
+		return FormatCSV, nil
+	case ".json":
+		return FormatJSON, nil
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON, nil
+	case ".parquet":
+		return FormatParquet, nil
+	case ".arrow", ".feather", ".ipc":
+		return FormatArrow, nil
+	case ".arrows":
+		return FormatArrowStream, nil
+	case ".xlsx":
+		return FormatExcel, nil
+	case ".koalas":
+		return FormatKoalas, nil
+	case ".fwf":
+		return FormatFixedWidth, nil
+	}
+	return "", fmt.Errorf("cannot detect file format of %s", path)
+}
+
+// compressionFromExt returns the compression named by a file extension
+func compressionFromExt(ext string) string {
+	switch strings.ToLower(ext) {
+	case ".gz", ".gzip":
+		return "gzip"
+	case ".zst", ".zstd":
+		return "zstd"
+	case ".bz2":
+		return "bzip2"
+	case ".xz":
+		return "xz"
+	}
+	return ""
+}
+
+// compressionMagic holds the leading bytes of each compression format, see
+// isBzip2 for the rest of the bzip2 check
+var compressionMagic = []struct {
+	name  string
+	magic []byte
+}{
+	{"gzip", []byte{0x1f, 0x8b}},
+	{"zstd", []byte{0x28, 0xb5, 0x2f, 0xfd}},
+	{"bzip2", []byte("BZh")},
+	{"xz", []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}},
+}
+
+// bzip2Block and bzip2End are the magic numbers of the first bzip2 block and
+// of the end of an empty stream, which follow the "BZh" header and block size
+var (
+	bzip2Block = []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}
+	bzip2End   = []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90}
+)
+
+// isBzip2 reports whether head starts a bzip2 stream. "BZh" alone is too
+// common in text, so the block size digit and the magic after it must match.
+func isBzip2(head []byte) bool {
+	if len(head) < 10 || !bytes.HasPrefix(head, []byte("BZh")) || head[3] < '1' || head[3] > '9' {
+		return false
+	}
+	return bytes.Equal(head[4:10], bzip2Block) || bytes.Equal(head[4:10], bzip2End)
+}
+
+// decompress peeks at the first bytes of r and, if they mark a compressed
+// stream, returns a reader of the decompressed data. Other input is returned
+// unchanged.
+func decompress(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(10)
+
+	compression := ""
+	for _, c := range compressionMagic {
+		if bytes.HasPrefix(head, c.magic) {
+			compression = c.name
+			break
+		}
+	}
+	if compression == "bzip2" && !isBzip2(head) {
+		compression = ""
+	}
+
+	switch compression {
+	case "gzip":
+		return gzip.NewReader(br)
+	case "zstd":
+		dec, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	case "bzip2":
+		return bzip2.NewReader(br, nil)
+	case "xz":
+		xr, err := xz.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(xr), nil
+	}
+	return io.NopCloser(br), nil
+}
+
+// compressWriter wraps w in a compressor of the given type
+func compressWriter(w io.Writer, compression string) (io.WriteCloser, error) {
+	switch compression {
+	case "gzip":
+		return gzip.NewWriter(w), nil
+	case "zstd":
+		return zstd.NewWriter(w)
+	case "bzip2":
+		return bzip2.NewWriter(w, nil)
+	case "xz":
+		return xz.NewWriter(w)
+	}
+	return nil, fmt.Errorf("invalid compression: %s", compression)
+}
+
+// fileReader reads decompressed data and closes both the decompressor and the file
+type fileReader struct {
+	io.ReadCloser
+	file *os.File
+}
+
+func (r *fileReader) Close() error {
+	err := r.ReadCloser.Close()
+	if fileErr := r.file.Close(); err == nil {
+		err = fileErr
+	}
+	return err
+}
+
+// fileWriter compresses data and closes both the compressor and the file
+type fileWriter struct {
+	io.WriteCloser
+	file *os.File
+}
+
+func (w *fileWriter) Close() error {
+	err := w.WriteCloser.Close()
+	if fileErr := w.file.Close(); err == nil {
+		err = fileErr
+	}
+	return err
+}
+
+// openFile opens path for reading, decompressing it when it is compressed
+func openFile(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := decompress(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error decompressing %s: %v", path, err)
+	}
+	return &fileReader{ReadCloser: r, file: file}, nil
+}
+
+// createFile creates path for writing, compressing it when its extension
+// names a compression format
+func createFile(path string) (io.WriteCloser, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	compression := compressionFromExt(filepath.Ext(path))
+	if compression == "" {
+		return file, nil
+	}
+
+	w, err := compressWriter(file, compression)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &fileWriter{WriteCloser: w, file: file}, nil
+}
@@ -0,0 +1,422 @@
+package dataframe
+
+import (
+	"bytes"
+	"koalas/series"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newFrame builds a DataFrame from alternating column names, datatypes and
+// values, failing the test on error
+func newFrame(t *testing.T, columns ...interface{}) *DataFrame {
+	t.Helper()
+	var list []*series.Series
+	for i := 0; i+2 < len(columns); i += 3 {
+		list = append(list, newSeries(columns[i].(string), columns[i+1].(string), columns[i+2].([]interface{})))
+	}
+	df, err := Create(list)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return df
+}
+
+// newCategory builds a category Series from string values, over the given
+// categories or the sorted distinct values when there are none
+func newCategory(t *testing.T, name string, values []interface{}, categories ...string) *series.Series {
+	t.Helper()
+	s, err := newSeries(name, "string", values).AsCategory(categories...)
+	if err != nil {
+		t.Fatalf("AsCategory: %v", err)
+	}
+	return s
+}
+
+// categoryLabels returns the labels of a category column in row order, along
+// with its categories
+func categoryLabels(t *testing.T, df *DataFrame, name string) ([]interface{}, []string) {
+	t.Helper()
+	col, exists := df.columns.Get(name)
+	if !exists {
+		t.Fatalf("column %s does not exist", name)
+	}
+	if col.Datatype != "category" || df.schema[name] != "category" {
+		t.Fatalf("column %s: datatype %s, schema %s, want category", name, col.Datatype, df.schema[name])
+	}
+	categories, err := col.Cat().Categories()
+	if err != nil {
+		t.Fatalf("Categories: %v", err)
+	}
+	labels := make([]interface{}, len(col.Data))
+	for i, entry := range col.Data {
+		if c, ok := entry.Value.(series.Category); ok {
+			labels[i] = c.Label()
+		}
+	}
+	return labels, categories
+}
+
+// columnValues returns the values of a column in row order
+func columnValues(t *testing.T, df *DataFrame, name string) []interface{} {
+	t.Helper()
+	col, exists := df.columns.Get(name)
+	if !exists {
+		t.Fatalf("column %s does not exist", name)
+	}
+	values := make([]interface{}, len(col.Data))
+	for i, entry := range col.Data {
+		values[i] = entry.Value
+	}
+	return values
+}
+
+// checkColumn fails the test when a column does not hold the datatype and
+// values given, or when the schema disagrees with its datatype
+func checkColumn(t *testing.T, df *DataFrame, name string, datatype string, values []interface{}) {
+	t.Helper()
+	col, exists := df.columns.Get(name)
+	if !exists {
+		t.Fatalf("column %s does not exist", name)
+	}
+	if col.Datatype != datatype || df.schema[name] != datatype {
+		t.Errorf("column %s: datatype %s, schema %s, want %s", name, col.Datatype, df.schema[name], datatype)
+	}
+	if got := columnValues(t, df, name); !reflect.DeepEqual(got, values) {
+		t.Errorf("column %s: got %v, want %v", name, got, values)
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		options CSVReadOptions
+		columns []string
+		types   []string
+		values  [][]interface{}
+	}{
+		{
+			name:    "infers types",
+			input:   "a,b,c,d\n1,1.5,true,x\n2,2,false,y\n",
+			options: DefaultCSVReadOptions(),
+			columns: []string{"a", "b", "c", "d"},
+			types:   []string{"int", "float", "bool", "string"},
+			values:  [][]interface{}{{1, 2}, {1.5, 2.0}, {true, false}, {"x", "y"}},
+		},
+		{
+			name:    "null tokens",
+			input:   "a,b\n1,NA\n,x\n",
+			options: DefaultCSVReadOptions(),
+			columns: []string{"a", "b"},
+			types:   []string{"int", "string"},
+			values:  [][]interface{}{{1, nil}, {nil, "x"}},
+		},
+		{
+			name:    "all null column",
+			input:   "a\nNA\n\n",
+			options: DefaultCSVReadOptions(),
+			columns: []string{"a"},
+			types:   []string{"string"},
+			values:  [][]interface{}{{nil}},
+		},
+		{
+			name:    "quoting",
+			input:   "a,b\n\"x,y\",\"say \"\"hi\"\"\"\n\"multi\nline\",z\n",
+			options: DefaultCSVReadOptions(),
+			columns: []string{"a", "b"},
+			types:   []string{"string", "string"},
+			values:  [][]interface{}{{"x,y", "multi\nline"}, {`say "hi"`, "z"}},
+		},
+		{
+			name:    "delimiter without header",
+			input:   "1;x\n2;y\n",
+			options: CSVReadOptions{Delimiter: ';'},
+			columns: []string{"column_0", "column_1"},
+			types:   []string{"int", "string"},
+			values:  [][]interface{}{{1, 2}, {"x", "y"}},
+		},
+		{
+			name:  "schema override",
+			input: "a,b\n1,2\n3,4\n",
+			options: CSVReadOptions{
+				Header: true,
+				Schema: map[string]string{"a": "float", "b": "string"},
+			},
+			columns: []string{"a", "b"},
+			types:   []string{"float", "string"},
+			values:  [][]interface{}{{1.0, 3.0}, {"2", "4"}},
+		},
+		{
+			name:    "comments",
+			input:   "a\n# skipped\n1\n",
+			options: CSVReadOptions{Header: true, Comment: '#'},
+			columns: []string{"a"},
+			types:   []string{"int"},
+			values:  [][]interface{}{{1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			df, err := ReadCSV(strings.NewReader(tt.input), tt.options)
+			if err != nil {
+				t.Fatalf("ReadCSV: %v", err)
+			}
+			if got := df.columns.Keys(); !reflect.DeepEqual(got, tt.columns) {
+				t.Fatalf("columns: got %v, want %v", got, tt.columns)
+			}
+			for i, name := range tt.columns {
+				checkColumn(t, df, name, tt.types[i], tt.values[i])
+			}
+		})
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		options CSVReadOptions
+		want    string
+	}{
+		{"empty input", "", DefaultCSVReadOptions(), "csv input is empty"},
+		{"invalid schema type", "a\n1\n", CSVReadOptions{Header: true, Schema: map[string]string{"a": "money"}}, "invalid type for column a"},
+		{"value does not fit schema", "a\nx\n", CSVReadOptions{Header: true, Schema: map[string]string{"a": "int"}}, "column a, row 0"},
+		{"ragged rows", "a,b\n1\n", DefaultCSVReadOptions(), "error reading csv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadCSV(strings.NewReader(tt.input), tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	df := newFrame(t,
+		"n", "int", []interface{}{1, nil},
+		"x", "float", []interface{}{3.14, 2.0},
+		"s", "string", []interface{}{"a,b", `q"`},
+	)
+	tests := []struct {
+		name    string
+		options CSVWriteOptions
+		want    string
+	}{
+		{"defaults", DefaultCSVWriteOptions(), "n,x,s\n1,3.14,\"a,b\"\n,2,\"q\"\"\"\n"},
+		{"zero value keeps every digit", CSVWriteOptions{Header: true}, "n,x,s\n1,3.14,\"a,b\"\n,2,\"q\"\"\"\n"},
+		{"fixed precision", CSVWriteOptions{FloatFormat: 'f', FloatPrecision: 2}, "1,3.14,\"a,b\"\n,2.00,\"q\"\"\"\n"},
+		{"zero precision", CSVWriteOptions{FloatFormat: 'f', ZeroPrecision: true}, "1,3,\"a,b\"\n,2,\"q\"\"\"\n"},
+		{"null value and delimiter", CSVWriteOptions{Delimiter: ';', NullValue: "NA"}, "1;3.14;a,b\nNA;2;\"q\"\"\"\n"},
+		{"quote all", CSVWriteOptions{Quoting: QuoteAll}, "\"1\",\"3.14\",\"a,b\"\n\"\",\"2\",\"q\"\"\"\n"},
+		{"quote non-numeric", CSVWriteOptions{Quoting: QuoteNonNumeric}, "1,3.14,\"a,b\"\n,2,\"q\"\"\"\n"},
+		{"quote none", CSVWriteOptions{Quoting: QuoteNone}, "1,3.14,a,b\n,2,q\"\n"},
+		{"crlf", CSVWriteOptions{UseCRLF: true}, "1,3.14,\"a,b\"\r\n,2,\"q\"\"\"\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := df.WriteCSV(&buf, tt.options); err != nil {
+				t.Fatalf("WriteCSV: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteCSVRoundTrip(t *testing.T) {
+	df := newFrame(t,
+		"n", "int", []interface{}{1, nil, 3},
+		"x", "float", []interface{}{0.1, 1e21, nil},
+		"s", "string", []interface{}{"line\nbreak", " lead", "plain"},
+	)
+	var buf bytes.Buffer
+	if err := df.WriteCSV(&buf, DefaultCSVWriteOptions()); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	back, err := ReadCSV(&buf, CSVReadOptions{Header: true, NullValues: []string{""}})
+	if err != nil {
+		t.Fatalf("ReadCSV: %v", err)
+	}
+	checkColumn(t, back, "n", "int", []interface{}{1, nil, 3})
+	checkColumn(t, back, "x", "float", []interface{}{0.1, 1e21, nil})
+	checkColumn(t, back, "s", "string", []interface{}{"line\nbreak", " lead", "plain"})
+}
+
+func TestWriteCSVInvalidDelimiter(t *testing.T) {
+	df := newFrame(t, "n", "int", []interface{}{1})
+	for _, delimiter := range []rune{'"', '\r', '\n'} {
+		if err := df.WriteCSV(&bytes.Buffer{}, CSVWriteOptions{Delimiter: delimiter}); err == nil {
+			t.Errorf("delimiter %q: expected an error", delimiter)
+		}
+	}
+}
+
+func TestFileRoundTrip(t *testing.T) {
+	df := newFrame(t,
+		"n", "int", []interface{}{1, nil, 3},
+		"s", "string", []interface{}{"a", "b", nil},
+	)
+	files := []string{
+		"data.csv", "data.csv.gz", "data.csv.zst", "data.csv.bz2", "data.csv.xz",
+		"data.json", "data.ndjson.gz", "data.parquet", "data.arrow", "data.arrows.bz2",
+		"data.xlsx", "data.koalas", "data.koalas.xz", "data.fwf",
+	}
+	dir := t.TempDir()
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			path := filepath.Join(dir, file)
+			if err := df.WriteFile(path, "", DefaultWriteOptions()); err != nil {
+				t.Fatalf("WriteFile: %v", err)
+			}
+			back, err := ReadFile(path, "", DefaultReadOptions())
+			if err != nil {
+				t.Fatalf("ReadFile: %v", err)
+			}
+			checkColumn(t, back, "n", "int", []interface{}{1, nil, 3})
+			checkColumn(t, back, "s", "string", []interface{}{"a", "b", nil})
+		})
+	}
+}
+
+func TestCategoryFileRoundTrip(t *testing.T) {
+	df, err := Create([]*series.Series{newCategory(t, "k", []interface{}{"low", nil, "high"}, "low", "mid", "high")})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	dir := t.TempDir()
+	for _, file := range []string{"data.parquet", "data.arrow", "data.koalas"} {
+		t.Run(file, func(t *testing.T) {
+			path := filepath.Join(dir, file)
+			if err := df.WriteFile(path, "", DefaultWriteOptions()); err != nil {
+				t.Fatalf("WriteFile: %v", err)
+			}
+			back, err := ReadFile(path, "", DefaultReadOptions())
+			if err != nil {
+				t.Fatalf("ReadFile: %v", err)
+			}
+			labels, categories := categoryLabels(t, back, "k")
+			if !reflect.DeepEqual(labels, []interface{}{"low", nil, "high"}) {
+				t.Errorf("labels: got %v", labels)
+			}
+			if !reflect.DeepEqual(categories, []string{"low", "mid", "high"}) {
+				t.Errorf("categories: got %v", categories)
+			}
+		})
+	}
+}
+
+func TestReadFileKoalasMapped(t *testing.T) {
+	// A truncated file fails in OpenKoalas, which names the file it maps
+	path := filepath.Join(t.TempDir(), "bad.koalas")
+	if err := os.WriteFile(path, append([]byte(nil), koalasMagic...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := ReadFile(path, "", DefaultReadOptions())
+	if err == nil || !strings.Contains(err.Error(), "error reading "+path) {
+		t.Errorf("got error %v, want an OpenKoalas error", err)
+	}
+}
+
+func TestReadFileBZhText(t *testing.T) {
+	// Text that merely starts with the bzip2 header is not decompressed
+	path := filepath.Join(t.TempDir(), "names.csv")
+	if err := os.WriteFile(path, []byte("BZh1,x\n2,y\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	df, err := ReadFile(path, "", DefaultReadOptions())
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	checkColumn(t, df, "BZh1", "int", []interface{}{2})
+}
+
+func TestIsBzip2(t *testing.T) {
+	tests := []struct {
+		name string
+		head []byte
+		want bool
+	}{
+		{"first block", append([]byte("BZh9"), bzip2Block...), true},
+		{"empty stream", append([]byte("BZh1"), bzip2End...), true},
+		{"text", []byte("BZh9 is a name"), false},
+		{"block size zero", append([]byte("BZh0"), bzip2Block...), false},
+		{"too short", []byte("BZh9"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isBzip2(tt.head); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteFileInvalidFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keep.csv")
+	if err := os.WriteFile(path, []byte("a\n1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	df := newFrame(t, "a", "int", []interface{}{2})
+	err := df.WriteFile(path, "tsv", DefaultWriteOptions())
+	if err == nil || !strings.Contains(err.Error(), "invalid file format: tsv") {
+		t.Fatalf("got error %v, want an invalid format error", err)
+	}
+	// The existing file is left alone
+	if b, _ := os.ReadFile(path); string(b) != "a\n1\n" {
+		t.Errorf("file changed to %q", b)
+	}
+
+	if _, err := ReadFile(path, "tsv", DefaultReadOptions()); err == nil {
+		t.Error("expected an invalid format error from ReadFile")
+	}
+	if _, err := ReadFile(filepath.Join(t.TempDir(), "data.unknown"), "", DefaultReadOptions()); err == nil {
+		t.Error("expected an error for an unknown extension")
+	}
+}
+
+func TestReadNDJSONFrame(t *testing.T) {
+	// One row per batch, with an all-null first batch and a late key
+	input := "{\"a\":null}\n{\"a\":2}\n{\"a\":3,\"b\":\"x\"}\n{\"c\":null}\n"
+	df, err := readNDJSONFrame(strings.NewReader(input), NDJSONReadOptions{BatchSize: 1})
+	if err != nil {
+		t.Fatalf("readNDJSONFrame: %v", err)
+	}
+	if got := df.columns.Keys(); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("columns: got %v", got)
+	}
+	checkColumn(t, df, "a", "int", []interface{}{nil, 2, 3, nil})
+	checkColumn(t, df, "b", "string", []interface{}{nil, nil, "x", nil})
+	checkColumn(t, df, "c", "string", []interface{}{nil, nil, nil, nil})
+
+	categories := "{\"k\":\"x\"}\n{\"k\":\"y\"}\n{\"k\":\"x\"}\n"
+	options := NDJSONReadOptions{BatchSize: 2, Schema: map[string]string{"k": "category"}}
+	df, err = readNDJSONFrame(strings.NewReader(categories), options)
+	if err != nil {
+		t.Fatalf("readNDJSONFrame: %v", err)
+	}
+	col, _ := df.columns.Get("k")
+	labels, err := col.Cat().AsString()
+	if err != nil {
+		t.Fatalf("AsString: %v", err)
+	}
+	df, _ = Create([]*series.Series{labels})
+	if col.Datatype != "category" || !reflect.DeepEqual(columnValues(t, df, "k"), []interface{}{"x", "y", "x"}) {
+		t.Errorf("got %s %v", col.Datatype, columnValues(t, df, "k"))
+	}
+
+	empty, err := readNDJSONFrame(strings.NewReader(""), DefaultNDJSONReadOptions())
+	if err != nil || empty.numCols != 0 {
+		t.Errorf("got %v columns, error %v", empty.numCols, err)
+	}
+}
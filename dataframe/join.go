@@ -39,6 +39,39 @@ func (df *DataFrame) Join(other *DataFrame, leftCols []string, rightCols []strin
 		}
 	}
 
+	// Promote join columns of different types to a common type
+	if how != "cross" && len(leftCols) > 0 {
+		leftCol, rightCol := GetJoinColumns(*df, *other, leftCols, rightCols)
+		// Join pairs are kept in a map, which cannot hold list or struct values
+		for _, datatype := range []string{leftCol.Datatype, rightCol.Datatype} {
+			if series.IsListDatatype(datatype) || datatype == "struct" {
+				return nil, fmt.Errorf("cannot join on %s columns %s and %s", datatype, leftCols[0], rightCols[0])
+			}
+		}
+		if leftCol.Datatype != rightCol.Datatype {
+			datatype, ok := series.PromoteType(leftCol.Datatype, rightCol.Datatype)
+			if !ok {
+				return nil, fmt.Errorf("data type mismatch for join columns %s and %s: %s != %s",
+					leftCols[0], rightCols[0], leftCol.Datatype, rightCol.Datatype)
+			}
+			var err error
+			if df, err = df.withConvertedColumn(leftCols[0], datatype); err != nil {
+				return nil, err
+			}
+			if other, err = other.withConvertedColumn(rightCols[0], datatype); err != nil {
+				return nil, err
+			}
+		} else if leftCol.Datatype == "category" {
+			// Recode category keys onto one dictionary so that they match by code
+			unified, err := series.UnifyCategories(leftCol, rightCol)
+			if err != nil {
+				return nil, err
+			}
+			df = df.withColumn(leftCols[0], unified[0])
+			other = other.withColumn(rightCols[0], unified[1])
+		}
+	}
+
 	//switch statement to select sub functions deepen on how
 	switch how {
 	case "inner":
@@ -64,7 +97,7 @@ func (df *DataFrame) innerJoin(other *DataFrame, leftCols []string, rightCols []
 	joinPairs := make(map[series.Entry][]series.Entry, 0)
 	for _, lVal := range leftCol.Data {
 		for _, RVal := range rightCol.Data {
-			if lVal.Value == RVal.Value {
+			if joinKeysMatch(lVal.Value, RVal.Value) {
 				joinPairs[lVal] = append(joinPairs[lVal], RVal)
 			}
 		}
@@ -142,7 +175,7 @@ func (df *DataFrame) leftJoin(other *DataFrame, leftCols []string, rightCols []s
 	joinPairs := make(map[series.Entry][]series.Entry, 0)
 	for _, lVal := range leftCol.Data {
 		for _, RVal := range rightCol.Data {
-			if lVal.Value == RVal.Value {
+			if joinKeysMatch(lVal.Value, RVal.Value) {
 				joinPairs[lVal] = append(joinPairs[lVal], RVal)
 			}
 		}
@@ -244,7 +277,7 @@ func (df *DataFrame) rightJoin(other *DataFrame, leftCols []string, rightCols []
 	joinPairs := make(map[series.Entry][]series.Entry, 0)
 	for _, rVal := range rightCol.Data {
 		for _, lVal := range leftCol.Data {
-			if rVal.Value == lVal.Value {
+			if joinKeysMatch(lVal.Value, rVal.Value) {
 				joinPairs[rVal] = append(joinPairs[rVal], lVal)
 			}
 		}
@@ -349,7 +382,7 @@ func (df *DataFrame) outerJoin(other *DataFrame, leftCols []string, rightCols []
 	// Build join pairs in both directions
 	for _, lVal := range leftCol.Data {
 		for _, rVal := range rightCol.Data {
-			if lVal.Value == rVal.Value {
+			if joinKeysMatch(lVal.Value, rVal.Value) {
 				leftToRight[lVal] = append(leftToRight[lVal], rVal)
 				rightToLeft[rVal] = append(rightToLeft[rVal], lVal)
 			}
@@ -540,3 +573,39 @@ func GetJoinColumns(df DataFrame, other DataFrame, leftCols []string, rightCols
 	rightCol, _ := other.columns.Get(rightCols[0])
 	return leftCol, rightCol
 }
+
+// joinKeysMatch reports whether two join keys match. As in SQL, a nil key
+// matches nothing, not even another nil key.
+func joinKeysMatch(a interface{}, b interface{}) bool {
+	return a != nil && b != nil && series.Equal(a, b)
+}
+
+// withConvertedColumn returns a shallow copy of the DataFrame with one column
+// converted to a datatype chosen by series.PromoteType
+func (df *DataFrame) withConvertedColumn(name string, datatype string) (*DataFrame, error) {
+	col, _ := df.columns.Get(name)
+	converted, err := convertSeries(col, datatype)
+	if err != nil {
+		return nil, err
+	}
+	return df.withColumn(name, converted), nil
+}
+
+// withColumn returns a shallow copy of the DataFrame with one column replaced
+func (df *DataFrame) withColumn(name string, replacement *series.Series) *DataFrame {
+	result := &DataFrame{
+		columns: NewOrderedMap(),
+		numCols: df.numCols,
+		numRows: df.numRows,
+		schema:  make(map[string]string),
+	}
+	for _, key := range df.columns.Keys() {
+		col, _ := df.columns.Get(key)
+		if key == name {
+			col = replacement
+		}
+		result.columns.Set(key, col)
+		result.schema[key] = col.Datatype
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+package dataframe
+
+import (
+	"fmt"
+	"koalas/series"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// joinedRows returns every row of a DataFrame as a string, sorted, so that
+// joins can be checked whatever the order of their rows
+func joinedRows(df *DataFrame) []string {
+	rows := make([]string, df.numRows)
+	for i := range rows {
+		rows[i] = fmt.Sprint(df.GetRow(i))
+	}
+	sort.Strings(rows)
+	return rows
+}
+
+func TestJoinCategory(t *testing.T) {
+	left, err := Create([]*series.Series{
+		newCategory(t, "k", []interface{}{"x", "y", "z"}),
+		newSeries("n", "int", []interface{}{1, 2, 3}),
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	right, err := Create([]*series.Series{
+		newCategory(t, "k", []interface{}{"y", "x", "w"}, "w", "y", "x"),
+		newSeries("m", "string", []interface{}{"b", "a", "c"}),
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	joined, err := left.Join(right, []string{"k"}, []string{"k"}, "", "inner")
+	if err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	want := []string{"[x 1 a]", "[y 2 b]"}
+	if got := joinedRows(joined); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestJoinNullKeys(t *testing.T) {
+	tests := []struct {
+		how  string
+		want []string
+	}{
+		{"inner", []string{"[1 10 a]"}},
+		{"left", []string{"[1 10 a]", "[<nil> 20 <nil>]", "[<nil> 30 <nil>]"}},
+		// Right joins put the key after the other left columns
+		{"right", []string{"[10 1 a]", "[<nil> <nil> b]"}},
+		{"outer", []string{"[1 10 a]", "[<nil> 20 <nil>]", "[<nil> 30 <nil>]", "[<nil> <nil> b]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.how, func(t *testing.T) {
+			left := newFrame(t,
+				"k", "int", []interface{}{1, nil, nil},
+				"n", "int", []interface{}{10, 20, 30},
+			)
+			right := newFrame(t,
+				"k", "int", []interface{}{1, nil},
+				"m", "string", []interface{}{"a", "b"},
+			)
+			joined, err := left.Join(right, []string{"k"}, []string{"k"}, "", tt.how)
+			if err != nil {
+				t.Fatalf("Join: %v", err)
+			}
+			if got := joinedRows(joined); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinDecimalScales(t *testing.T) {
+	left := newFrame(t,
+		"k", "decimal(4,1)", []interface{}{mustDecimal(t, 15, 1), mustDecimal(t, 20, 1), nil},
+		"n", "int", []interface{}{1, 2, 3},
+	)
+	right := newFrame(t,
+		"k", "decimal(6,3)", []interface{}{mustDecimal(t, 2000, 3), mustDecimal(t, 1500, 3), mustDecimal(t, 1510, 3)},
+		"m", "string", []interface{}{"a", "b", "c"},
+	)
+	joined, err := left.Join(right, []string{"k"}, []string{"k"}, "", "inner")
+	if err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	// The keys are promoted to decimal(6,3), so that 1.5 matches 1.500
+	want := []string{"[1.500 1 b]", "[2.000 2 a]"}
+	if got := joinedRows(joined); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := joined.schema["k"]; got != "decimal(6,3)" {
+		t.Errorf("key datatype: got %s", got)
+	}
+}
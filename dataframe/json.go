@@ -0,0 +1,399 @@
+package dataframe
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"koalas/series"
+)
+
+// JSONOrient selects the layout used to encode a DataFrame as JSON
+type JSONOrient string
+
+const (
+	// JSONRecords is a list of row objects: [{"a": 1, "b": "x"}, ...]. The
+	// columns are the keys of every record, missing keys hold nil.
+	JSONRecords JSONOrient = "records"
+	// JSONColumns is an object of column arrays: {"a": [1, ...], "b": ["x", ...]}
+	JSONColumns JSONOrient = "columns"
+	// JSONSplit holds the column names, schema and row arrays separately:
+	// {"columns": ["a", "b"], "schema": {"a": "int", "b": "string"}, "data": [[1, "x"], ...]}
+	JSONSplit JSONOrient = "split"
+)
+
+// splitJSON is the wire format of the split orientation
+type splitJSON struct {
+	Columns []string            `json:"columns"`
+	Schema  map[string]string   `json:"schema"`
+	Data    [][]json.RawMessage `json:"data"`
+}
+
+// MarshalJSON encodes the DataFrame using the split orientation, which keeps the schema
+func (df *DataFrame) MarshalJSON() ([]byte, error) {
+	return df.ToJSON(JSONSplit)
+}
+
+// UnmarshalJSON decodes a DataFrame in any orientation, detecting which one was used
+func (df *DataFrame) UnmarshalJSON(b []byte) error {
+	result, err := ReadJSON(b, "", nil, nil)
+	if err != nil {
+		return err
+	}
+	*df = *result
+	return nil
+}
+
+// ToJSON encodes the DataFrame in the given orientation, with columns in their current order
+func (df *DataFrame) ToJSON(orient JSONOrient) ([]byte, error) {
+	colNames := df.columns.Keys()
+	var buf bytes.Buffer
+
+	switch orient {
+	case JSONRecords:
+		buf.WriteByte('[')
+		for row := 0; row < df.numRows; row++ {
+			if row > 0 {
+				buf.WriteByte(',')
+			}
+			if err := df.writeJSONRecord(&buf, row); err != nil {
+				return nil, err
+			}
+		}
+		buf.WriteByte(']')
+
+	case JSONColumns:
+		buf.WriteByte('{')
+		for i, name := range colNames {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONString(&buf, name)
+			buf.WriteString(":[")
+			col, _ := df.columns.Get(name)
+			for j, entry := range col.Data {
+				if j > 0 {
+					buf.WriteByte(',')
+				}
+				if err := writeJSONValue(&buf, entry.Value); err != nil {
+					return nil, fmt.Errorf("column %s, row %d: %v", name, j, err)
+				}
+			}
+			buf.WriteByte(']')
+		}
+		buf.WriteByte('}')
+
+	case JSONSplit:
+		header, err := json.Marshal(struct {
+			Columns []string          `json:"columns"`
+			Schema  map[string]string `json:"schema"`
+		}{colNames, df.schema})
+		if err != nil {
+			return nil, err
+		}
+		// Reuse the header object and append the data array to it
+		buf.Write(header[:len(header)-1])
+		buf.WriteString(`,"data":[`)
+		for row := 0; row < df.numRows; row++ {
+			if row > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('[')
+			for i, val := range df.GetRow(row) {
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				if err := writeJSONValue(&buf, val); err != nil {
+					return nil, fmt.Errorf("column %s, row %d: %v", colNames[i], row, err)
+				}
+			}
+			buf.WriteByte(']')
+		}
+		buf.WriteString("]}")
+
+	default:
+		return nil, fmt.Errorf("invalid json orientation: %s", orient)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ReadJSON decodes a DataFrame from JSON in the given orientation. An empty
+// orientation is detected from the shape of the input. Column types come from
+// the split schema, then from the schema argument, and are otherwise inferred.
+// Datetime strings are parsed with layouts, or the default layouts when empty.
+// Columns of strings that all match one of the layouts are inferred as datetime.
+func ReadJSON(b []byte, orient JSONOrient, schema map[string]string, layouts []string) (*DataFrame, error) {
+	trimmed := bytes.TrimSpace(b)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("json input is empty")
+	}
+
+	if orient == "" {
+		detected, err := detectJSONOrient(trimmed)
+		if err != nil {
+			return nil, err
+		}
+		orient = detected
+	}
+
+	var names []string
+	var columns [][]interface{}
+	switch orient {
+	case JSONRecords:
+		var records []json.RawMessage
+		if err := json.Unmarshal(trimmed, &records); err != nil {
+			return nil, fmt.Errorf("error decoding json records: %v", err)
+		}
+		// Columns are the keys of every record in the order they first appear,
+		// and records missing a key hold nil
+		positions := make(map[string]int)
+		for i, record := range records {
+			keys, values, err := decodeOrderedObject(record)
+			if err != nil {
+				return nil, fmt.Errorf("record %d: %v", i, err)
+			}
+			for _, key := range keys {
+				if _, exists := positions[key]; !exists {
+					positions[key] = len(names)
+					names = append(names, key)
+					columns = append(columns, make([]interface{}, i))
+				}
+			}
+			for j, name := range names {
+				columns[j] = append(columns[j], values[name])
+			}
+		}
+
+	case JSONColumns:
+		keys, values, err := decodeOrderedObject(trimmed)
+		if err != nil {
+			return nil, err
+		}
+		names = keys
+		columns = make([][]interface{}, len(names))
+		for i, name := range names {
+			col, ok := values[name].([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("column '%s' is not a json array", name)
+			}
+			columns[i] = col
+		}
+
+	case JSONSplit:
+		var split splitJSON
+		if err := json.Unmarshal(trimmed, &split); err != nil {
+			return nil, fmt.Errorf("error decoding json split: %v", err)
+		}
+		names = split.Columns
+		columns = make([][]interface{}, len(names))
+		for i, row := range split.Data {
+			if len(row) != len(names) {
+				return nil, fmt.Errorf("row length mismatch: expected %d columns, got %d", len(names), len(row))
+			}
+			for j, msg := range row {
+				value, err := series.DecodeJSONValue(msg)
+				if err != nil {
+					return nil, fmt.Errorf("row %d: %v", i, err)
+				}
+				columns[j] = append(columns[j], value)
+			}
+		}
+		// The declared schema wins over the schema argument
+		if split.Schema != nil {
+			merged := make(map[string]string)
+			for name, datatype := range schema {
+				merged[name] = datatype
+			}
+			for name, datatype := range split.Schema {
+				merged[name] = datatype
+			}
+			schema = merged
+		}
+
+	default:
+		return nil, fmt.Errorf("invalid json orientation: %s", orient)
+	}
+
+	return fromJSONColumns(names, columns, schema, layouts, nil)
+}
+
+// fromJSONColumns converts decoded JSON columns into a DataFrame. Errors name
+// the row of a value, or its line when lines holds the line of every row.
+func fromJSONColumns(names []string, columns [][]interface{}, schema map[string]string, layouts []string, lines []int) (*DataFrame, error) {
+	seriesList := make([]*series.Series, len(names))
+	for i, name := range names {
+		datatype, exists := schema[name]
+		if !exists {
+			inferred, err := series.InferJSONType(columns[i])
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %v", name, err)
+			}
+			datatype = inferred
+			if datatype == "string" && isJSONDatetime(columns[i], layouts) {
+				datatype = "datetime"
+			}
+		} else if !series.IsValidDatatype(datatype) {
+			return nil, fmt.Errorf("invalid type for column %s: %s", name, datatype)
+		}
+
+		values := make([]interface{}, len(columns[i]))
+		for j, raw := range columns[i] {
+			var value interface{}
+			var err error
+			if s, ok := raw.(string); ok && datatype == "datetime" {
+				value, err = series.ParseDatetime(s, layouts)
+			} else {
+				value, err = series.FromJSONValue(raw, readDatatype(datatype))
+			}
+			if err != nil {
+				if lines != nil {
+					return nil, fmt.Errorf("column %s, line %d: %v", name, lines[j], err)
+				}
+				return nil, fmt.Errorf("column %s, row %d: %v", name, j, err)
+			}
+			values[j] = value
+		}
+		col, err := newColumnSeries(name, datatype, values)
+		if err != nil {
+			return nil, err
+		}
+		seriesList[i] = col
+	}
+
+	return Create(seriesList)
+}
+
+// isJSONDatetime reports whether layouts are given and every non-nil value is
+// a string that one of them parses
+func isJSONDatetime(values []interface{}, layouts []string) bool {
+	if len(layouts) == 0 {
+		return false
+	}
+	seen := false
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		s, ok := value.(string)
+		if !ok {
+			return false
+		}
+		if _, err := series.ParseDatetime(s, layouts); err != nil {
+			return false
+		}
+		seen = true
+	}
+	return seen
+}
+
+// detectJSONOrient guesses the orientation from the shape of the input. An
+// object is split when it only holds an array of column names, an array of
+// rows as long as the names and optionally a schema. Without a schema, such
+// an object is also a valid columns object when there are as many rows as
+// names, and the orientation has to be given.
+func detectJSONOrient(b []byte) (JSONOrient, error) {
+	if b[0] == '[' {
+		return JSONRecords, nil
+	}
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(b, &probe); err != nil {
+		return JSONColumns, nil
+	}
+	for key := range probe {
+		if key != "columns" && key != "data" && key != "schema" {
+			return JSONColumns, nil
+		}
+	}
+
+	var columns []string
+	var data [][]json.RawMessage
+	if json.Unmarshal(probe["columns"], &columns) != nil || columns == nil ||
+		json.Unmarshal(probe["data"], &data) != nil || data == nil {
+		return JSONColumns, nil
+	}
+	for _, row := range data {
+		if len(row) != len(columns) {
+			return JSONColumns, nil
+		}
+	}
+	if raw, hasSchema := probe["schema"]; hasSchema {
+		var schema map[string]string
+		if json.Unmarshal(raw, &schema) != nil {
+			return JSONColumns, nil
+		}
+		return JSONSplit, nil
+	}
+	if len(data) == len(columns) {
+		return "", fmt.Errorf("cannot tell json split from columns orientation, give the orientation")
+	}
+	return JSONSplit, nil
+}
+
+// decodeOrderedObject decodes a JSON object, returning its keys in the order
+// they appear alongside the decoded values
+func decodeOrderedObject(b []byte) ([]string, map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, nil, fmt.Errorf("expected a json object")
+	}
+
+	keys := []string{}
+	values := make(map[string]interface{})
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := tok.(string)
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		if _, exists := values[key]; exists {
+			return nil, nil, fmt.Errorf("duplicate key: %s", key)
+		}
+		keys = append(keys, key)
+		values[key] = value
+	}
+	return keys, values, nil
+}
+
+// writeJSONRecord writes one row as a JSON object with keys in column order
+func (df *DataFrame) writeJSONRecord(buf *bytes.Buffer, row int) error {
+	colNames := df.columns.Keys()
+	buf.WriteByte('{')
+	for i, val := range df.GetRow(row) {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeJSONString(buf, colNames[i])
+		buf.WriteByte(':')
+		if err := writeJSONValue(buf, val); err != nil {
+			return fmt.Errorf("column %s, row %d: %v", colNames[i], row, err)
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// writeJSONValue appends the JSON encoding of a single value
+func writeJSONValue(buf *bytes.Buffer, val interface{}) error {
+	raw, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	buf.Write(raw)
+	return nil
+}
+
+// writeJSONString appends a quoted JSON string
+func writeJSONString(buf *bytes.Buffer, s string) {
+	raw, _ := json.Marshal(s)
+	buf.Write(raw)
+}
@@ -0,0 +1,148 @@
+package dataframe
+
+import (
+	"encoding/json"
+	"koalas/series"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	df := newFrame(t,
+		"n", "int", []interface{}{1, nil},
+		"x", "float", []interface{}{2.0, 2.5},
+		"s", "string", []interface{}{"a", "b"},
+		"b", "bool", []interface{}{true, nil},
+	)
+	for _, orient := range []JSONOrient{JSONRecords, JSONColumns, JSONSplit} {
+		t.Run(string(orient), func(t *testing.T) {
+			b, err := df.ToJSON(orient)
+			if err != nil {
+				t.Fatalf("ToJSON: %v", err)
+			}
+			// Whole floats such as 2.0 are written as 2, so only split keeps
+			// the float type without a schema
+			schema := map[string]string{"x": "float"}
+			back, err := ReadJSON(b, "", schema, nil)
+			if err != nil {
+				t.Fatalf("ReadJSON: %v", err)
+			}
+			if got := back.columns.Keys(); !reflect.DeepEqual(got, []string{"n", "x", "s", "b"}) {
+				t.Errorf("columns: got %v", got)
+			}
+			checkColumn(t, back, "n", "int", []interface{}{1, nil})
+			checkColumn(t, back, "x", "float", []interface{}{2.0, 2.5})
+			checkColumn(t, back, "s", "string", []interface{}{"a", "b"})
+			checkColumn(t, back, "b", "bool", []interface{}{true, nil})
+		})
+	}
+}
+
+func TestJSONMarshalKeepsSchema(t *testing.T) {
+	df := newFrame(t, "x", "float", []interface{}{1.0, 2.0})
+	b, err := json.Marshal(df)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var back DataFrame
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	checkColumn(t, &back, "x", "float", []interface{}{1.0, 2.0})
+}
+
+func TestSeriesJSONRoundTrip(t *testing.T) {
+	s, _ := series.Create("x", "float", []interface{}{1.0, 2.5})
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var back series.Series
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if back.Name != "x" || back.Datatype != "float" || !reflect.DeepEqual(back.Data, s.Data) {
+		t.Errorf("got %+v, want %+v", back, *s)
+	}
+}
+
+func TestDetectJSONOrient(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    JSONOrient
+		wantErr bool
+	}{
+		{"records", `[{"a":1}]`, JSONRecords, false},
+		{"columns", `{"a":[1,2],"b":["x","y"]}`, JSONColumns, false},
+		{"split", `{"columns":["a"],"schema":{"a":"int"},"data":[[1],[2]]}`, JSONSplit, false},
+		{"split without schema", `{"columns":["a"],"data":[[1],[2]]}`, JSONSplit, false},
+		{"columns named columns and data", `{"columns":[1,2],"data":["x","y"]}`, JSONColumns, false},
+		{"columns with string names", `{"columns":["p","q"],"data":["x","y"]}`, JSONColumns, false},
+		{"rows not as long as names", `{"columns":["a","b"],"data":[[1],[2],[3]]}`, JSONColumns, false},
+		{"other keys", `{"columns":["a"],"data":[[1]],"extra":[1]}`, JSONColumns, false},
+		{"ambiguous", `{"columns":["p","q"],"data":[[1,2],[3,4]]}`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := detectJSONOrient([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadJSONColumnsNamedColumnsAndData(t *testing.T) {
+	df, err := ReadJSON([]byte(`{"columns":[1,2],"data":["x","y"]}`), "", nil, nil)
+	if err != nil {
+		t.Fatalf("ReadJSON: %v", err)
+	}
+	checkColumn(t, df, "columns", "int", []interface{}{1, 2})
+	checkColumn(t, df, "data", "string", []interface{}{"x", "y"})
+}
+
+func TestReadJSONRecordsLateKeys(t *testing.T) {
+	df, err := ReadJSON([]byte(`[{"a":1},{"b":"x","a":2},{"c":true}]`), "", nil, nil)
+	if err != nil {
+		t.Fatalf("ReadJSON: %v", err)
+	}
+	if got := df.columns.Keys(); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("columns: got %v", got)
+	}
+	checkColumn(t, df, "a", "int", []interface{}{1, 2, nil})
+	checkColumn(t, df, "b", "string", []interface{}{nil, "x", nil})
+	checkColumn(t, df, "c", "bool", []interface{}{nil, nil, true})
+}
+
+func TestReadJSONErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		orient JSONOrient
+		schema map[string]string
+		want   string
+	}{
+		{"empty", "  ", "", nil, "json input is empty"},
+		{"invalid orient", `[]`, "table", nil, "invalid json orientation"},
+		{"column not an array", `{"a":1}`, JSONColumns, nil, "column 'a' is not a json array"},
+		{"row length", `{"columns":["a","b"],"schema":{},"data":[[1]]}`, JSONSplit, nil, "row length mismatch"},
+		{"mixed types", `{"a":[1,"x"]}`, "", nil, "mixed types"},
+		{"schema mismatch", `{"a":["x"]}`, "", map[string]string{"a": "int"}, "column a, row 0"},
+		{"ambiguous", `{"columns":["p","q"],"data":[[1,2],[3,4]]}`, "", nil, "give the orientation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadJSON([]byte(tt.input), tt.orient, tt.schema, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,508 @@
+package dataframe
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"koalas/series"
+	"math"
+	"os"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+)
+
+/*
+The koalas file format stores each Series as one contiguous block so that a
+single column can be read without touching the others:
+
+	magic "KOALAS01"
+	column block 0
+	...
+	column block n-1
+	footer (JSON)
+	footer length (uint64)
+	magic "KOALAS01"
+
+Every column block holds, in order:
+
+	validity bitmap  ceil(rows/8) bytes, bit set when the value is not nil
+	entry indices    rows x int64
+	values           rows x int64 for "int" and the other signed integers,
+	                 for "duration" in nanoseconds and for "datetime" in
+	                 nanoseconds since the Unix epoch, read back in UTC,
+	                 rows x uint64 for the unsigned integers, rows x float64
+	                 for "float" and "float32", ceil(rows/8) bytes for "bool",
+	                 for "string" (rows+1) x int64 offsets followed by the
+	                 string bytes, rows x int64 codes for "category" and
+	                 rows x 128-bit coefficients for decimals, each the low
+	                 uint64 followed by the high int64 of a two's complement
+	                 integer
+
+Nil values are stored as zero. The footer records the datatype of every
+column, such as "decimal(10,2)" whose scale gives the coefficients their
+meaning, and for a category column the labels of its dictionary in code
+order, so that code i stands for categories[i].
+
+All numbers are little endian.
+*/
+
+// koalasMagic starts and ends every koalas file
+var koalasMagic = []byte("KOALAS01")
+
+// koalasFooter is the JSON footer of a koalas file
+type koalasFooter struct {
+	Version int            `json:"version"`
+	Rows    int            `json:"rows"`
+	Columns []koalasColumn `json:"columns"`
+}
+
+// koalasColumn records where a column block lives in the file
+type koalasColumn struct {
+	Name       string   `json:"name"`
+	Datatype   string   `json:"datatype"`
+	Offset     int64    `json:"offset"`
+	Length     int64    `json:"length"`
+	Categories []string `json:"categories,omitempty"` // Dictionary of a category column
+}
+
+// KoalasFile is an open koalas file. Columns are only decoded when a Select or
+// ReadAll asks for them.
+type KoalasFile struct {
+	data   []byte
+	footer koalasFooter
+	unmap  func() error
+}
+
+// WriteKoalas writes the DataFrame in the koalas columnar format
+func (df *DataFrame) WriteKoalas(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(koalasMagic); err != nil {
+		return err
+	}
+
+	footer := koalasFooter{Version: 1, Rows: df.numRows}
+	offset := int64(len(koalasMagic))
+	for _, name := range df.columns.Keys() {
+		col, _ := df.columns.Get(name)
+		block, err := encodeKoalasColumn(col)
+		if err != nil {
+			return fmt.Errorf("column %s: %v", name, err)
+		}
+		if _, err := bw.Write(block); err != nil {
+			return err
+		}
+		var categories []string
+		if col.Datatype == "category" {
+			if categories, err = col.Cat().Categories(); err != nil {
+				return fmt.Errorf("column %s: %v", name, err)
+			}
+		}
+		footer.Columns = append(footer.Columns, koalasColumn{
+			Name:       name,
+			Datatype:   df.schema[name],
+			Offset:     offset,
+			Length:     int64(len(block)),
+			Categories: categories,
+		})
+		offset += int64(len(block))
+	}
+
+	raw, err := json.Marshal(footer)
+	if err != nil {
+		return err
+	}
+	if _, err := bw.Write(raw); err != nil {
+		return err
+	}
+	if err := binary.Write(bw, binary.LittleEndian, uint64(len(raw))); err != nil {
+		return err
+	}
+	if _, err := bw.Write(koalasMagic); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// OpenKoalas memory maps a koalas file and reads its footer. The file must be
+// closed once the DataFrames needed from it have been read.
+func OpenKoalas(path string) (*KoalasFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data, unmap, err := mmapFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error mapping %s: %v", path, err)
+	}
+
+	kf, err := newKoalasFile(data)
+	if err != nil {
+		unmap()
+		return nil, fmt.Errorf("error reading %s: %v", path, err)
+	}
+	kf.unmap = unmap
+	return kf, nil
+}
+
+// readKoalasFile memory maps a koalas file and reads every column of it into
+// a new DataFrame
+func readKoalasFile(path string) (*DataFrame, error) {
+	kf, err := OpenKoalas(path)
+	if err != nil {
+		return nil, err
+	}
+	defer kf.Close()
+	return kf.ReadAll()
+}
+
+// ReadKoalas reads every column of a koalas file into a new DataFrame. Use
+// OpenKoalas to read only some of the columns of a file on disk.
+func ReadKoalas(r io.Reader) (*DataFrame, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading koalas file: %v", err)
+	}
+	kf, err := newKoalasFile(data)
+	if err != nil {
+		return nil, err
+	}
+	return kf.ReadAll()
+}
+
+// newKoalasFile checks the magic bytes of a koalas file and decodes its footer
+func newKoalasFile(data []byte) (*KoalasFile, error) {
+	minLen := 2*len(koalasMagic) + 8
+	if len(data) < minLen ||
+		!bytes.Equal(data[:len(koalasMagic)], koalasMagic) ||
+		!bytes.Equal(data[len(data)-len(koalasMagic):], koalasMagic) {
+		return nil, fmt.Errorf("not a koalas file")
+	}
+
+	end := len(data) - len(koalasMagic) - 8
+	footerLen := binary.LittleEndian.Uint64(data[end:])
+	if footerLen > uint64(end-len(koalasMagic)) {
+		return nil, fmt.Errorf("corrupt koalas footer")
+	}
+
+	footerStart := end - int(footerLen)
+
+	kf := &KoalasFile{data: data}
+	if err := json.Unmarshal(data[footerStart:end], &kf.footer); err != nil {
+		return nil, fmt.Errorf("corrupt koalas footer: %v", err)
+	}
+	if kf.footer.Version != 1 {
+		return nil, fmt.Errorf("unsupported koalas version: %d", kf.footer.Version)
+	}
+	// Every row takes at least its 8 byte index in a column block, which
+	// bounds the row count by the size of the file
+	if kf.footer.Rows < 0 || kf.footer.Rows > footerStart/8 {
+		return nil, fmt.Errorf("corrupt koalas footer: invalid row count %d", kf.footer.Rows)
+	}
+	for _, col := range kf.footer.Columns {
+		if col.Offset < int64(len(koalasMagic)) || col.Length < 0 || col.Length > int64(footerStart)-col.Offset {
+			return nil, fmt.Errorf("corrupt koalas footer: column %s is out of range", col.Name)
+		}
+	}
+	return kf, nil
+}
+
+// NumRows returns the number of rows stored in the file
+func (kf *KoalasFile) NumRows() int {
+	return kf.footer.Rows
+}
+
+// Columns returns the name and datatype of every column in the file
+func (kf *KoalasFile) Columns() []ColumnInfo {
+	columns := make([]ColumnInfo, len(kf.footer.Columns))
+	for i, col := range kf.footer.Columns {
+		columns[i] = ColumnInfo{Name: col.Name, DataType: col.Datatype}
+	}
+	return columns
+}
+
+// Select decodes only the given columns into a new DataFrame
+func (kf *KoalasFile) Select(columns []string) (*DataFrame, error) {
+	if kf.data == nil {
+		return nil, fmt.Errorf("koalas file is closed")
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns specified for selection")
+	}
+
+	seriesList := make([]*series.Series, len(columns))
+	for i, name := range columns {
+		var found *koalasColumn
+		for j := range kf.footer.Columns {
+			if kf.footer.Columns[j].Name == name {
+				found = &kf.footer.Columns[j]
+				break
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("column '%s' does not exist in koalas file", name)
+		}
+
+		block := kf.data[found.Offset : found.Offset+found.Length]
+		s, err := decodeKoalasColumn(*found, kf.footer.Rows, block)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %v", name, err)
+		}
+		seriesList[i] = s
+	}
+	return Create(seriesList)
+}
+
+// ReadAll decodes every column into a new DataFrame
+func (kf *KoalasFile) ReadAll() (*DataFrame, error) {
+	if kf.data == nil {
+		return nil, fmt.Errorf("koalas file is closed")
+	}
+	if len(kf.footer.Columns) == 0 {
+		return Create(nil)
+	}
+	names := make([]string, len(kf.footer.Columns))
+	for i, col := range kf.footer.Columns {
+		names[i] = col.Name
+	}
+	return kf.Select(names)
+}
+
+// Close releases the memory mapping. DataFrames already read stay valid, and
+// Select or ReadAll on a closed file return an error.
+func (kf *KoalasFile) Close() error {
+	kf.data = nil
+	if kf.unmap == nil {
+		return nil
+	}
+	err := kf.unmap()
+	kf.unmap = nil
+	return err
+}
+
+// encodeKoalasColumn lays out one Series as a column block
+func encodeKoalasColumn(col *series.Series) ([]byte, error) {
+	rows := len(col.Data)
+	bitmapLen := (rows + 7) / 8
+	buf := make([]byte, bitmapLen, bitmapLen+rows*16)
+
+	// Validity bitmap
+	for i, entry := range col.Data {
+		if entry.Value != nil {
+			buf[i/8] |= 1 << (i % 8)
+		}
+	}
+
+	// Entry indices
+	for _, entry := range col.Data {
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(int64(entry.Index)))
+	}
+
+	// Values, with nil stored as the zero value
+	switch col.Datatype {
+	case "int", "int8", "int16", "int32", "int64":
+		for _, entry := range col.Data {
+			v, _ := series.ConvertValue(entry.Value, "int64")
+			n, _ := v.(int64)
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(n))
+		}
+	case "duration":
+		for _, entry := range col.Data {
+			v, _ := entry.Value.(time.Duration)
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(v))
+		}
+	case "datetime":
+		for _, entry := range col.Data {
+			var n int64
+			if v, ok := entry.Value.(time.Time); ok {
+				var err error
+				if n, err = unixNanos(v); err != nil {
+					return nil, err
+				}
+			}
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(n))
+		}
+	case "category":
+		for _, entry := range col.Data {
+			v, _ := entry.Value.(series.Category)
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(v.Code()))
+		}
+	case "uint8", "uint16", "uint32", "uint64":
+		for _, entry := range col.Data {
+			v, _ := series.ConvertValue(entry.Value, "uint64")
+			n, _ := v.(uint64)
+			buf = binary.LittleEndian.AppendUint64(buf, n)
+		}
+	case "float", "float32":
+		for _, entry := range col.Data {
+			v, _ := series.ConvertValue(entry.Value, "float")
+			f, _ := v.(float64)
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(f))
+		}
+	case "bool":
+		values := make([]byte, bitmapLen)
+		for i, entry := range col.Data {
+			if v, _ := entry.Value.(bool); v {
+				values[i/8] |= 1 << (i % 8)
+			}
+		}
+		buf = append(buf, values...)
+	case "string":
+		offset := uint64(0)
+		buf = binary.LittleEndian.AppendUint64(buf, offset)
+		for _, entry := range col.Data {
+			v, _ := entry.Value.(string)
+			offset += uint64(len(v))
+			buf = binary.LittleEndian.AppendUint64(buf, offset)
+		}
+		for _, entry := range col.Data {
+			v, _ := entry.Value.(string)
+			buf = append(buf, v...)
+		}
+	default:
+		if !series.IsDecimalDatatype(col.Datatype) {
+			return nil, fmt.Errorf("unsupported type for koalas format: %s", col.Datatype)
+		}
+		for _, entry := range col.Data {
+			var n decimal128.Num
+			if v, ok := entry.Value.(series.Decimal); ok {
+				n = decimal128.FromBigInt(v.Coefficient())
+			}
+			buf = binary.LittleEndian.AppendUint64(buf, n.LowBits())
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(n.HighBits()))
+		}
+	}
+	return buf, nil
+}
+
+// decodeKoalasColumn copies a column block out of the file into a new Series
+func decodeKoalasColumn(column koalasColumn, rows int, block []byte) (*series.Series, error) {
+	name, datatype := column.Name, column.Datatype
+	bitmapLen := (rows + 7) / 8
+	if rows < 0 || rows > len(block)/8 || len(block) < bitmapLen+rows*8 {
+		return nil, fmt.Errorf("column block is truncated")
+	}
+	validity := block[:bitmapLen]
+	indices := block[bitmapLen : bitmapLen+rows*8]
+	values := block[bitmapLen+rows*8:]
+
+	data := make([]series.Entry, rows)
+	for i := range data {
+		data[i].Index = int(int64(binary.LittleEndian.Uint64(indices[i*8:])))
+	}
+
+	switch datatype {
+	case "duration", "datetime":
+		if len(values) < rows*8 {
+			return nil, fmt.Errorf("column block is truncated")
+		}
+		for i := range data {
+			n := int64(binary.LittleEndian.Uint64(values[i*8:]))
+			if datatype == "duration" {
+				data[i].Value = time.Duration(n)
+			} else {
+				data[i].Value = time.Unix(0, n).UTC()
+			}
+		}
+	case "int", "int8", "int16", "int32", "int64", "uint8", "uint16", "uint32", "uint64", "float", "float32":
+		if len(values) < rows*8 {
+			return nil, fmt.Errorf("column block is truncated")
+		}
+		for i := range data {
+			if validity[i/8]&(1<<(i%8)) == 0 {
+				continue
+			}
+			bits := binary.LittleEndian.Uint64(values[i*8:])
+			var value interface{}
+			switch datatype {
+			case "float", "float32":
+				value = math.Float64frombits(bits)
+			case "uint8", "uint16", "uint32", "uint64":
+				value = bits
+			default:
+				value = int64(bits)
+			}
+			converted, err := series.ConvertValue(value, datatype)
+			if err != nil {
+				return nil, err
+			}
+			data[i].Value = converted
+		}
+	case "category":
+		if len(values) < rows*8 {
+			return nil, fmt.Errorf("column block is truncated")
+		}
+		categories, err := series.NewCategories(column.Categories)
+		if err != nil {
+			return nil, err
+		}
+		for i := range data {
+			if validity[i/8]&(1<<(i%8)) == 0 {
+				continue
+			}
+			code := int64(binary.LittleEndian.Uint64(values[i*8:]))
+			if data[i].Value = categories.Value(int(code)); data[i].Value == nil {
+				return nil, fmt.Errorf("category code out of range: %d", code)
+			}
+		}
+	case "bool":
+		if len(values) < bitmapLen {
+			return nil, fmt.Errorf("column block is truncated")
+		}
+		for i := range data {
+			data[i].Value = values[i/8]&(1<<(i%8)) != 0
+		}
+	case "string":
+		if len(values) < (rows+1)*8 {
+			return nil, fmt.Errorf("column block is truncated")
+		}
+		offsets := values[:(rows+1)*8]
+		strData := values[(rows+1)*8:]
+		for i := range data {
+			start := binary.LittleEndian.Uint64(offsets[i*8:])
+			end := binary.LittleEndian.Uint64(offsets[(i+1)*8:])
+			if start > end || end > uint64(len(strData)) {
+				return nil, fmt.Errorf("column block is truncated")
+			}
+			data[i].Value = string(strData[start:end])
+		}
+	default:
+		_, scale, ok := series.ParseDecimalType(datatype)
+		if !ok {
+			return nil, fmt.Errorf("unsupported type for koalas format: %s", datatype)
+		}
+		if len(values) < rows*16 {
+			return nil, fmt.Errorf("column block is truncated")
+		}
+		for i := range data {
+			if validity[i/8]&(1<<(i%8)) == 0 {
+				continue
+			}
+			lo := binary.LittleEndian.Uint64(values[i*16:])
+			hi := int64(binary.LittleEndian.Uint64(values[i*16+8:]))
+			value, err := series.NewDecimalFromBig(decimal128.New(hi, lo).BigInt(), scale)
+			if err != nil {
+				return nil, err
+			}
+			data[i].Value = value
+		}
+	}
+
+	// Clear the values the bitmap marks as nil
+	for i := range data {
+		if validity[i/8]&(1<<(i%8)) == 0 {
+			data[i].Value = nil
+		}
+	}
+
+	return &series.Series{
+		Name:     name,
+		Datatype: datatype,
+		Data:     data,
+	}, nil
+}
@@ -0,0 +1,175 @@
+package dataframe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"koalas/series"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// koalasFile lays out a koalas file from a column area and a footer
+func koalasFile(t *testing.T, body []byte, footer koalasFooter) []byte {
+	t.Helper()
+	raw, err := json.Marshal(footer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	buf.Write(koalasMagic)
+	buf.Write(body)
+	buf.Write(raw)
+	binary.Write(&buf, binary.LittleEndian, uint64(len(raw)))
+	buf.Write(koalasMagic)
+	return buf.Bytes()
+}
+
+// mustDecimal returns unscaled divided by 10 to the power scale
+func mustDecimal(t *testing.T, unscaled int64, scale int) series.Decimal {
+	t.Helper()
+	d, err := series.NewDecimal(unscaled, scale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestKoalasRoundTrip(t *testing.T) {
+	df := newFrame(t,
+		"n", "int", []interface{}{1, nil, -3},
+		"i8", "int8", []interface{}{int8(-1), int8(2), nil},
+		"u", "uint64", []interface{}{uint64(1 << 63), nil, uint64(0)},
+		"x", "float", []interface{}{1.5, 2.5, nil},
+		"f32", "float32", []interface{}{float32(0.5), nil, float32(1)},
+		"b", "bool", []interface{}{true, nil, false},
+		"s", "string", []interface{}{"a", "", nil},
+		"d", "decimal(5,2)", []interface{}{mustDecimal(t, -1234, 2), nil, mustDecimal(t, 5, 2)},
+	)
+	var buf bytes.Buffer
+	if err := df.WriteKoalas(&buf); err != nil {
+		t.Fatalf("WriteKoalas: %v", err)
+	}
+	back, err := ReadKoalas(&buf)
+	if err != nil {
+		t.Fatalf("ReadKoalas: %v", err)
+	}
+	for _, name := range df.columns.Keys() {
+		col, _ := df.columns.Get(name)
+		checkColumn(t, back, name, col.Datatype, columnValues(t, df, name))
+	}
+}
+
+func TestKoalasTimes(t *testing.T) {
+	when := time.Date(2024, 3, 1, 12, 30, 0, 5, time.FixedZone("CET", 3600))
+	df := newFrame(t,
+		"t", "datetime", []interface{}{when, nil, time.Unix(0, 0).UTC()},
+		"d", "duration", []interface{}{-time.Hour, time.Duration(0), nil},
+	)
+	var buf bytes.Buffer
+	if err := df.WriteKoalas(&buf); err != nil {
+		t.Fatalf("WriteKoalas: %v", err)
+	}
+	back, err := ReadKoalas(&buf)
+	if err != nil {
+		t.Fatalf("ReadKoalas: %v", err)
+	}
+	// Times come back as the same instant in UTC
+	checkColumn(t, back, "t", "datetime", []interface{}{when.UTC(), nil, time.Unix(0, 0).UTC()})
+	checkColumn(t, back, "d", "duration", []interface{}{-time.Hour, time.Duration(0), nil})
+
+	old := newFrame(t, "t", "datetime", []interface{}{time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)})
+	if err := old.WriteKoalas(&bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "column t") {
+		t.Errorf("got error %v, want an out of range error", err)
+	}
+}
+
+func TestOpenKoalas(t *testing.T) {
+	df := newFrame(t,
+		"a", "int", []interface{}{1, 2},
+		"b", "string", []interface{}{"x", "y"},
+	)
+	path := filepath.Join(t.TempDir(), "data.koalas")
+	if err := df.WriteFile(path, "", DefaultWriteOptions()); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	kf, err := OpenKoalas(path)
+	if err != nil {
+		t.Fatalf("OpenKoalas: %v", err)
+	}
+	if kf.NumRows() != 2 {
+		t.Errorf("got %d rows, want 2", kf.NumRows())
+	}
+	want := []ColumnInfo{{Name: "a", DataType: "int"}, {Name: "b", DataType: "string"}}
+	if got := kf.Columns(); !reflect.DeepEqual(got, want) {
+		t.Errorf("columns: got %v, want %v", got, want)
+	}
+
+	selected, err := kf.Select([]string{"b"})
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	checkColumn(t, selected, "b", "string", []interface{}{"x", "y"})
+	if _, err := kf.Select([]string{"c"}); err == nil || !strings.Contains(err.Error(), "column 'c' does not exist") {
+		t.Errorf("got error %v, want a missing column error", err)
+	}
+
+	if err := kf.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	// Frames read before closing stay valid
+	checkColumn(t, selected, "b", "string", []interface{}{"x", "y"})
+	if _, err := kf.Select([]string{"a"}); err == nil || !strings.Contains(err.Error(), "closed") {
+		t.Errorf("got error %v, want a closed file error", err)
+	}
+	if _, err := kf.ReadAll(); err == nil {
+		t.Error("expected an error from ReadAll after Close")
+	}
+	if err := kf.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+}
+
+func TestReadKoalasCorrupt(t *testing.T) {
+	// One int column of two rows: bitmap, indices and values
+	block := make([]byte, 1+2*8+2*8)
+	column := koalasColumn{Name: "a", Datatype: "int", Offset: int64(len(koalasMagic)), Length: int64(len(block))}
+	valid := koalasFile(t, block, koalasFooter{Version: 1, Rows: 2, Columns: []koalasColumn{column}})
+	if _, err := ReadKoalas(bytes.NewReader(valid)); err != nil {
+		t.Fatalf("valid file: %v", err)
+	}
+
+	withColumn := func(change func(*koalasColumn)) []koalasColumn {
+		c := column
+		change(&c)
+		return []koalasColumn{c}
+	}
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"not koalas", []byte("hello"), "not a koalas file"},
+		{"footer length", append(append(append([]byte{}, koalasMagic...), 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f), koalasMagic...), "corrupt koalas footer"},
+		{"version", koalasFile(t, nil, koalasFooter{Version: 2}), "unsupported koalas version"},
+		{"negative rows", koalasFile(t, block, koalasFooter{Version: 1, Rows: -1, Columns: []koalasColumn{column}}), "invalid row count -1"},
+		{"huge rows", koalasFile(t, block, koalasFooter{Version: 1, Rows: 1 << 60, Columns: []koalasColumn{column}}), "invalid row count"},
+		{"column in footer", koalasFile(t, block, koalasFooter{Version: 1, Rows: 2, Columns: withColumn(func(c *koalasColumn) { c.Length += 10 })}), "column a is out of range"},
+		{"column in magic", koalasFile(t, block, koalasFooter{Version: 1, Rows: 2, Columns: withColumn(func(c *koalasColumn) { c.Offset = 0 })}), "column a is out of range"},
+		{"length overflow", koalasFile(t, block, koalasFooter{Version: 1, Rows: 2, Columns: withColumn(func(c *koalasColumn) { c.Length = 1<<63 - 1 })}), "column a is out of range"},
+		{"truncated block", koalasFile(t, block, koalasFooter{Version: 1, Rows: 2, Columns: withColumn(func(c *koalasColumn) { c.Length = 20 })}), "column block is truncated"},
+		{"unsupported type", koalasFile(t, block, koalasFooter{Version: 1, Rows: 2, Columns: withColumn(func(c *koalasColumn) { c.Datatype = "list<int>" })}), "unsupported type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadKoalas(bytes.NewReader(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package dataframe
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"koalas/series"
+)
+
+// NDJSONReadOptions configures how ReadNDJSON splits its input into batches
+type NDJSONReadOptions struct {
+	BatchSize       int               // Number of rows per batch, defaults to 10000
+	Schema          map[string]string // Explicit datatypes that override inference
+	DatetimeLayouts []string          // Layouts tried for datetime columns, setting them also infers datetime columns
+}
+
+// DefaultNDJSONReadOptions returns the options used for a typical NDJSON file
+func DefaultNDJSONReadOptions() NDJSONReadOptions {
+	return NDJSONReadOptions{
+		BatchSize: 10000,
+	}
+}
+
+// ReadNDJSON streams newline-delimited JSON objects, calling fn with a DataFrame
+// for every BatchSize rows. Columns are added in the order their keys first
+// appear, and rows without a key hold nil, so a column first seen after the
+// first batch is missing from the batches before it. The datatype of a column
+// is fixed by the first batch holding one of its values, and a column that has
+// only held nil so far is typed as string, which Union accepts in place of any
+// datatype. Errors give the line of the input they come from. Returning an
+// error from fn stops the read. Compressed input is decompressed.
+func ReadNDJSON(r io.Reader, options NDJSONReadOptions, fn func(batch *DataFrame) error) error {
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultNDJSONReadOptions().BatchSize
+	}
+
+	dr, err := decompress(r)
+	if err != nil {
+		return fmt.Errorf("error decompressing ndjson: %v", err)
+	}
+	defer dr.Close()
+
+	reader := bufio.NewReader(dr)
+	schema := make(map[string]string, len(options.Schema))
+	for name, datatype := range options.Schema {
+		schema[name] = datatype
+	}
+	var names []string
+	positions := make(map[string]int)
+	var columns [][]interface{}
+	var lines []int // Line of each buffered row
+	line := 0
+
+	// flush turns the buffered rows into a DataFrame and hands it to fn
+	flush := func() error {
+		if len(lines) == 0 {
+			return nil
+		}
+		batch, err := fromJSONColumns(names, columns, schema, options.DatetimeLayouts, lines)
+		if err != nil {
+			return err
+		}
+		// Lock the datatype of every column holding a value, so that later
+		// batches are read the same way
+		for _, name := range names {
+			if _, locked := schema[name]; locked {
+				continue
+			}
+			if col, _ := batch.columns.Get(name); col.NullCount() < col.Len() {
+				schema[name] = col.Datatype
+			}
+		}
+		columns = make([][]interface{}, len(names))
+		lines = nil
+		return fn(batch)
+	}
+
+	for {
+		raw, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return fmt.Errorf("error reading ndjson: %v", readErr)
+		}
+		if len(raw) > 0 {
+			line++
+		}
+
+		raw = bytes.TrimSpace(raw)
+		if len(raw) > 0 {
+			keys, values, err := decodeOrderedObject(raw)
+			if err != nil {
+				return fmt.Errorf("line %d: %v", line, err)
+			}
+
+			// New keys add a column that is nil in the rows buffered before
+			for _, key := range keys {
+				if _, exists := positions[key]; !exists {
+					positions[key] = len(names)
+					names = append(names, key)
+					columns = append(columns, make([]interface{}, len(lines)))
+				}
+			}
+			for i, name := range names {
+				columns[i] = append(columns[i], values[name])
+			}
+
+			lines = append(lines, line)
+			if len(lines) == options.BatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	return flush()
+}
+
+// readNDJSONFrame reads every batch of NDJSON input into one DataFrame. The
+// values are collected per column and the frame is built once, so columns
+// first seen in a later batch are padded with nil and a column keeps the type
+// of the first batch that held one of its values.
+func readNDJSONFrame(r io.Reader, options NDJSONReadOptions) (*DataFrame, error) {
+	names := []string{}
+	positions := make(map[string]int)
+	datatypes := []string{}
+	columns := [][]interface{}{}
+	rows := 0
+
+	err := ReadNDJSON(r, options, func(batch *DataFrame) error {
+		for _, name := range batch.columns.Keys() {
+			col, _ := batch.columns.Get(name)
+			i, seen := positions[name]
+			if !seen {
+				i = len(names)
+				positions[name] = i
+				names = append(names, name)
+				datatypes = append(datatypes, "")
+				columns = append(columns, make([]interface{}, rows, rows+batch.numRows))
+			}
+
+			// Categories are encoded per batch, so collect their labels
+			if col.Datatype == "category" {
+				labels, err := col.Cat().AsString()
+				if err != nil {
+					return err
+				}
+				col = labels
+				if datatypes[i] == "" {
+					datatypes[i] = "category"
+				}
+			} else if col.NullCount() < col.Len() {
+				if datatypes[i] != "" && datatypes[i] != col.Datatype {
+					return fmt.Errorf("column %s changes type from %s to %s", name, datatypes[i], col.Datatype)
+				}
+				datatypes[i] = col.Datatype
+			}
+			for _, entry := range col.Data {
+				columns[i] = append(columns[i], entry.Value)
+			}
+		}
+
+		// Columns missing from this batch hold nil for its rows
+		rows += batch.numRows
+		for i := range columns {
+			for len(columns[i]) < rows {
+				columns[i] = append(columns[i], nil)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seriesList := make([]*series.Series, len(names))
+	for i, name := range names {
+		datatype := datatypes[i]
+		if datatype == "" {
+			datatype = "string"
+		}
+		col, err := newColumnSeries(name, datatype, columns[i])
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %v", name, err)
+		}
+		seriesList[i] = col
+	}
+	return Create(seriesList)
+}
+
+// WriteNDJSON writes every row of the DataFrame as one JSON object per line,
+// with keys in column order
+func (df *DataFrame) WriteNDJSON(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	var buf bytes.Buffer
+	for row := 0; row < df.numRows; row++ {
+		buf.Reset()
+		if err := df.writeJSONRecord(&buf, row); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		if _, err := bw.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
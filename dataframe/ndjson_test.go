@@ -0,0 +1,124 @@
+package dataframe
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readBatches reads NDJSON input, returning every batch
+func readBatches(t *testing.T, input string, options NDJSONReadOptions) ([]*DataFrame, error) {
+	t.Helper()
+	var batches []*DataFrame
+	err := ReadNDJSON(strings.NewReader(input), options, func(batch *DataFrame) error {
+		batches = append(batches, batch)
+		return nil
+	})
+	return batches, err
+}
+
+func TestReadNDJSONBatches(t *testing.T) {
+	input := "{\"a\":1,\"b\":\"x\"}\n\n{\"a\":2,\"b\":\"y\"}\n{\"a\":3}\n"
+	batches, err := readBatches(t, input, NDJSONReadOptions{BatchSize: 2})
+	if err != nil {
+		t.Fatalf("ReadNDJSON: %v", err)
+	}
+	if len(batches) != 2 {
+		t.Fatalf("got %d batches, want 2", len(batches))
+	}
+	checkColumn(t, batches[0], "a", "int", []interface{}{1, 2})
+	checkColumn(t, batches[0], "b", "string", []interface{}{"x", "y"})
+	checkColumn(t, batches[1], "a", "int", []interface{}{3})
+	checkColumn(t, batches[1], "b", "string", []interface{}{nil})
+}
+
+func TestReadNDJSONNullFirstBatch(t *testing.T) {
+	input := "{\"a\":null}\n{\"a\":5}\n{\"a\":6}\n"
+	batches, err := readBatches(t, input, NDJSONReadOptions{BatchSize: 1})
+	if err != nil {
+		t.Fatalf("ReadNDJSON: %v", err)
+	}
+	checkColumn(t, batches[0], "a", "string", []interface{}{nil})
+	checkColumn(t, batches[1], "a", "int", []interface{}{5})
+	checkColumn(t, batches[2], "a", "int", []interface{}{6})
+
+	// The all-null batch unions with the typed ones
+	combined, err := batches[0].Union(batches[1])
+	if err != nil {
+		t.Fatalf("Union: %v", err)
+	}
+	checkColumn(t, combined, "a", "int", []interface{}{nil, 5})
+}
+
+func TestReadNDJSONLateKeys(t *testing.T) {
+	input := "{\"a\":1}\n{\"a\":2,\"b\":true}\n{\"b\":false}\n"
+	batches, err := readBatches(t, input, DefaultNDJSONReadOptions())
+	if err != nil {
+		t.Fatalf("ReadNDJSON: %v", err)
+	}
+	if got := batches[0].columns.Keys(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("columns: got %v", got)
+	}
+	checkColumn(t, batches[0], "a", "int", []interface{}{1, 2, nil})
+	checkColumn(t, batches[0], "b", "bool", []interface{}{nil, true, false})
+}
+
+func TestReadNDJSONSchema(t *testing.T) {
+	input := "{\"a\":1}\n{\"a\":2}\n"
+	batches, err := readBatches(t, input, NDJSONReadOptions{Schema: map[string]string{"a": "float"}})
+	if err != nil {
+		t.Fatalf("ReadNDJSON: %v", err)
+	}
+	checkColumn(t, batches[0], "a", "float", []interface{}{1.0, 2.0})
+}
+
+func TestReadNDJSONErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		options NDJSONReadOptions
+		want    string
+	}{
+		{"invalid json on a later line", "{\"a\":1}\n\n{\"a\":\n", DefaultNDJSONReadOptions(), "line 3"},
+		{"not an object", "[1]\n", DefaultNDJSONReadOptions(), "line 1: expected a json object"},
+		{"type change after lock", "{\"a\":1}\n\n{\"a\":\"x\"}\n", NDJSONReadOptions{BatchSize: 1}, "column a, line 3"},
+		{"schema mismatch", "{\"a\":1}\n{\"a\":\"x\"}\n", NDJSONReadOptions{Schema: map[string]string{"a": "int"}}, "column a, line 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readBatches(t, tt.input, tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadNDJSONCallbackError(t *testing.T) {
+	calls := 0
+	err := ReadNDJSON(strings.NewReader("{\"a\":1}\n{\"a\":2}\n"), NDJSONReadOptions{BatchSize: 1}, func(batch *DataFrame) error {
+		calls++
+		return fmt.Errorf("stop")
+	})
+	if err == nil || err.Error() != "stop" || calls != 1 {
+		t.Errorf("got error %v after %d calls, want stop after 1", err, calls)
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	df := newFrame(t,
+		"b", "string", []interface{}{"x", nil},
+		"a", "int", []interface{}{1, 2},
+	)
+	var buf bytes.Buffer
+	if err := df.WriteNDJSON(&buf); err != nil {
+		t.Fatalf("WriteNDJSON: %v", err)
+	}
+	want := "{\"b\":\"x\",\"a\":1}\n{\"b\":null,\"a\":2}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,200 @@
+package dataframe
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+// ParquetReadOptions configures ReadParquet
+type ParquetReadOptions struct {
+	Columns []string // Only read these columns, in this order. Empty reads every column
+}
+
+// ParquetWriteOptions configures WriteParquet
+type ParquetWriteOptions struct {
+	RowGroupSize int64  // Maximum number of rows per row group, defaults to 64Ki
+	Compression  string // One of none, snappy, gzip, brotli, zstd or lz4, defaults to snappy
+}
+
+// DefaultParquetWriteOptions returns the options used to write a typical Parquet file
+func DefaultParquetWriteOptions() ParquetWriteOptions {
+	return ParquetWriteOptions{
+		RowGroupSize: 64 * 1024,
+		Compression:  "snappy",
+	}
+}
+
+// parquetCodecs maps compression names to Parquet codecs
+var parquetCodecs = map[string]compress.Compression{
+	"none":   compress.Codecs.Uncompressed,
+	"snappy": compress.Codecs.Snappy,
+	"gzip":   compress.Codecs.Gzip,
+	"brotli": compress.Codecs.Brotli,
+	"zstd":   compress.Codecs.Zstd,
+	"lz4":    compress.Codecs.Lz4Raw,
+}
+
+// parquetDurationKey marks the int64 fields that hold the nanoseconds of a
+// "duration" column, as Parquet has no duration type
+const parquetDurationKey = "koalas.duration"
+
+// ReadParquet reads a Parquet file into a new DataFrame. Integer and float
+// columns keep their width, with 64-bit columns read as "int" and "float",
+// UTF8 columns as "string", boolean columns as "bool" and timestamps as
+// "datetime" in UTC. Null values become nil. Category, duration and int64
+// columns written by WriteParquet are read back as "category", "duration" and
+// "int64".
+func ReadParquet(r ReaderAtSeeker, options ParquetReadOptions) (*DataFrame, error) {
+	pf, err := file.NewParquetReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("error opening parquet file: %v", err)
+	}
+	defer pf.Close()
+
+	fr, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		return nil, fmt.Errorf("error opening parquet file: %v", err)
+	}
+
+	// Resolve the projected column names to leaf column indexes
+	var indices []int
+	if len(options.Columns) > 0 {
+		parquetSchema := pf.MetaData().Schema
+		for _, name := range options.Columns {
+			idx := parquetSchema.ColumnIndexByName(name)
+			if idx < 0 {
+				return nil, fmt.Errorf("column '%s' does not exist in parquet file", name)
+			}
+			indices = append(indices, idx)
+		}
+	} else {
+		for i := 0; i < pf.MetaData().Schema.NumColumns(); i++ {
+			indices = append(indices, i)
+		}
+	}
+
+	rowGroups := make([]int, pf.NumRowGroups())
+	for i := range rowGroups {
+		rowGroups[i] = i
+	}
+
+	tbl, err := fr.ReadRowGroups(context.Background(), indices, rowGroups)
+	if err != nil {
+		return nil, fmt.Errorf("error reading parquet file: %v", err)
+	}
+	defer tbl.Release()
+
+	df, err := fromArrowTable(tbl)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range tbl.Schema().Fields() {
+		if field.Metadata.FindKey(parquetDurationKey) < 0 {
+			continue
+		}
+		col, _ := df.columns.Get(field.Name)
+		for i, entry := range col.Data {
+			if n, ok := entry.Value.(int); ok {
+				col.Data[i].Value = time.Duration(n)
+			}
+		}
+		col.Datatype = "duration"
+		df.schema[field.Name] = "duration"
+	}
+
+	// Keep the order the columns were asked for
+	if len(options.Columns) > 0 {
+		if err := df.OrderColumns(options.Columns); err != nil {
+			return nil, err
+		}
+	}
+	return df, nil
+}
+
+// WriteParquet writes the DataFrame as a Parquet file. Every column is written
+// as an optional field so that nil values are kept as nulls.
+func (df *DataFrame) WriteParquet(w io.Writer, options ParquetWriteOptions) error {
+	if options.RowGroupSize <= 0 {
+		options.RowGroupSize = DefaultParquetWriteOptions().RowGroupSize
+	}
+	if options.Compression == "" {
+		options.Compression = DefaultParquetWriteOptions().Compression
+	}
+	codec, exists := parquetCodecs[options.Compression]
+	if !exists {
+		return fmt.Errorf("invalid parquet compression: %s", options.Compression)
+	}
+
+	rec, err := df.arrowRecord(memory.DefaultAllocator)
+	if err != nil {
+		return err
+	}
+	rec = durationsAsInt64(rec)
+	defer rec.Release()
+
+	props := parquet.NewWriterProperties(
+		parquet.WithMaxRowGroupLength(options.RowGroupSize),
+		parquet.WithCompression(codec),
+	)
+	// The file writer closes w if it can, but w belongs to the caller
+	// Storing the Arrow schema lets category columns be read back as dictionaries
+	arrowProps := pqarrow.NewArrowWriterProperties(pqarrow.WithStoreSchema())
+	fw, err := pqarrow.NewFileWriter(rec.Schema(), struct{ io.Writer }{w}, props, arrowProps)
+	if err != nil {
+		return fmt.Errorf("error creating parquet writer: %v", err)
+	}
+
+	// Write splits the record into row groups of at most RowGroupSize rows
+	if err := fw.Write(rec); err != nil {
+		fw.Close()
+		return fmt.Errorf("error writing parquet file: %v", err)
+	}
+
+	return fw.Close()
+}
+
+// durationsAsInt64 releases rec and returns it with every duration column
+// swapped for an int64 column of the same nanoseconds, marked with
+// parquetDurationKey
+func durationsAsInt64(rec arrow.RecordBatch) arrow.RecordBatch {
+	fields := append([]arrow.Field(nil), rec.Schema().Fields()...)
+	columns := append([]arrow.Array(nil), rec.Columns()...)
+	var converted []arrow.Array
+	for i, field := range fields {
+		if field.Type.ID() != arrow.DURATION {
+			continue
+		}
+		// Both types store int64 values, so the buffers are shared
+		data := columns[i].Data()
+		ints := array.NewData(arrow.PrimitiveTypes.Int64, data.Len(), data.Buffers(), nil, data.NullN(), data.Offset())
+		columns[i] = array.NewInt64Data(ints)
+		ints.Release()
+		converted = append(converted, columns[i])
+		fields[i] = arrow.Field{
+			Name:     field.Name,
+			Type:     arrow.PrimitiveTypes.Int64,
+			Nullable: field.Nullable,
+			Metadata: arrow.NewMetadata([]string{parquetDurationKey}, []string{"ns"}),
+		}
+	}
+	if len(converted) == 0 {
+		return rec
+	}
+
+	result := array.NewRecordBatch(arrow.NewSchema(fields, nil), columns, rec.NumRows())
+	for _, arr := range converted {
+		arr.Release()
+	}
+	rec.Release()
+	return result
+}
@@ -0,0 +1,101 @@
+package dataframe
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParquetRoundTrip(t *testing.T) {
+	df := newFrame(t,
+		"n", "int", []interface{}{1, nil, 3},
+		"x", "float", []interface{}{1.5, 2.5, nil},
+		"s", "string", []interface{}{nil, "b", "c"},
+		"b", "bool", []interface{}{true, false, nil},
+	)
+	for _, compression := range []string{"none", "snappy", "gzip", "zstd"} {
+		t.Run(compression, func(t *testing.T) {
+			var buf bytes.Buffer
+			options := ParquetWriteOptions{RowGroupSize: 2, Compression: compression}
+			if err := df.WriteParquet(&buf, options); err != nil {
+				t.Fatalf("WriteParquet: %v", err)
+			}
+			back, err := ReadParquet(bytes.NewReader(buf.Bytes()), ParquetReadOptions{})
+			if err != nil {
+				t.Fatalf("ReadParquet: %v", err)
+			}
+			checkColumn(t, back, "n", "int", []interface{}{1, nil, 3})
+			checkColumn(t, back, "x", "float", []interface{}{1.5, 2.5, nil})
+			checkColumn(t, back, "s", "string", []interface{}{nil, "b", "c"})
+			checkColumn(t, back, "b", "bool", []interface{}{true, false, nil})
+		})
+	}
+}
+
+func TestParquetSizedRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sizedFrame(t).WriteParquet(&buf, DefaultParquetWriteOptions()); err != nil {
+		t.Fatalf("WriteParquet: %v", err)
+	}
+	back, err := ReadParquet(bytes.NewReader(buf.Bytes()), ParquetReadOptions{})
+	if err != nil {
+		t.Fatalf("ReadParquet: %v", err)
+	}
+	checkSizedFrame(t, back)
+}
+
+func TestParquetTimes(t *testing.T) {
+	when := time.Date(2024, 3, 1, 12, 30, 0, 5, time.FixedZone("CET", 3600))
+	df := newFrame(t,
+		"t", "datetime", []interface{}{when, nil},
+		"d", "duration", []interface{}{nil, 1500 * time.Millisecond},
+	)
+	var buf bytes.Buffer
+	if err := df.WriteParquet(&buf, DefaultParquetWriteOptions()); err != nil {
+		t.Fatalf("WriteParquet: %v", err)
+	}
+	back, err := ReadParquet(bytes.NewReader(buf.Bytes()), ParquetReadOptions{})
+	if err != nil {
+		t.Fatalf("ReadParquet: %v", err)
+	}
+	checkColumn(t, back, "t", "datetime", []interface{}{when.UTC(), nil})
+	checkColumn(t, back, "d", "duration", []interface{}{nil, 1500 * time.Millisecond})
+}
+
+func TestParquetProjection(t *testing.T) {
+	df := newFrame(t,
+		"a", "int", []interface{}{1, 2},
+		"b", "string", []interface{}{"x", "y"},
+		"c", "bool", []interface{}{true, false},
+	)
+	var buf bytes.Buffer
+	if err := df.WriteParquet(&buf, DefaultParquetWriteOptions()); err != nil {
+		t.Fatalf("WriteParquet: %v", err)
+	}
+
+	back, err := ReadParquet(bytes.NewReader(buf.Bytes()), ParquetReadOptions{Columns: []string{"c", "a"}})
+	if err != nil {
+		t.Fatalf("ReadParquet: %v", err)
+	}
+	if got := back.columns.Keys(); !reflect.DeepEqual(got, []string{"c", "a"}) {
+		t.Errorf("columns: got %v, want [c a]", got)
+	}
+	checkColumn(t, back, "a", "int", []interface{}{1, 2})
+
+	_, err = ReadParquet(bytes.NewReader(buf.Bytes()), ParquetReadOptions{Columns: []string{"missing"}})
+	if err == nil || !strings.Contains(err.Error(), "column 'missing' does not exist") {
+		t.Errorf("got error %v, want a missing column error", err)
+	}
+}
+
+func TestParquetErrors(t *testing.T) {
+	df := newFrame(t, "a", "int", []interface{}{1})
+	if err := df.WriteParquet(&bytes.Buffer{}, ParquetWriteOptions{Compression: "lzma"}); err == nil {
+		t.Error("expected an error for an invalid compression")
+	}
+	if _, err := ReadParquet(bytes.NewReader([]byte("not parquet")), ParquetReadOptions{}); err == nil {
+		t.Error("expected an error for invalid input")
+	}
+}
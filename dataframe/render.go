@@ -0,0 +1,174 @@
+package dataframe
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"koalas/series"
+	"strings"
+)
+
+// ToMarkdown writes the DataFrame as a GitHub flavoured Markdown table, with
+// numeric columns right aligned
+func (df *DataFrame) ToMarkdown(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	colNames := df.columns.Keys()
+
+	// Markdown needs at least three dashes in each separator cell
+	maxWidths := df.columnWidths(colNames, markdownCell)
+	for i := range maxWidths {
+		if maxWidths[i] < 3 {
+			maxWidths[i] = 3
+		}
+	}
+
+	// Write headers
+	fmt.Fprintf(bw, "| %-*s |", maxWidths[0], "Index")
+	for i, name := range colNames {
+		fmt.Fprintf(bw, " %-*s |", maxWidths[i+1], markdownCell(name))
+	}
+	bw.WriteString("\n")
+
+	// Write separator line, using the schema for alignment
+	fmt.Fprintf(bw, "| %s |", strings.Repeat("-", maxWidths[0]-1)+":")
+	for i, name := range colNames {
+		if df.isNumeric(name) {
+			fmt.Fprintf(bw, " %s |", strings.Repeat("-", maxWidths[i+1]-1)+":")
+		} else {
+			fmt.Fprintf(bw, " %s |", strings.Repeat("-", maxWidths[i+1]))
+		}
+	}
+	bw.WriteString("\n")
+
+	// Write data rows
+	for row := 0; row < df.numRows; row++ {
+		fmt.Fprintf(bw, "| %*d |", maxWidths[0], row)
+		for i, val := range df.GetRow(row) {
+			if df.isNumeric(colNames[i]) {
+				fmt.Fprintf(bw, " %*s |", maxWidths[i+1], markdownCell(val))
+			} else {
+				fmt.Fprintf(bw, " %-*s |", maxWidths[i+1], markdownCell(val))
+			}
+		}
+		bw.WriteString("\n")
+	}
+
+	return bw.Flush()
+}
+
+// ToHTML writes the DataFrame as an HTML table. The head holds the column
+// names followed by a row with each column's datatype.
+func (df *DataFrame) ToHTML(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	colNames := df.columns.Keys()
+
+	bw.WriteString("<table>\n")
+
+	// Write headers and datatypes
+	bw.WriteString("  <thead>\n    <tr>\n      <th>Index</th>\n")
+	for _, name := range colNames {
+		fmt.Fprintf(bw, "      <th>%s</th>\n", html.EscapeString(name))
+	}
+	bw.WriteString("    </tr>\n    <tr>\n      <th></th>\n")
+	for _, name := range colNames {
+		fmt.Fprintf(bw, "      <th>%s</th>\n", html.EscapeString(df.schema[name]))
+	}
+	bw.WriteString("    </tr>\n  </thead>\n")
+
+	// Write data rows
+	bw.WriteString("  <tbody>\n")
+	for row := 0; row < df.numRows; row++ {
+		fmt.Fprintf(bw, "    <tr>\n      <th>%d</th>\n", row)
+		for _, val := range df.GetRow(row) {
+			fmt.Fprintf(bw, "      <td>%s</td>\n", html.EscapeString(cellText(val)))
+		}
+		bw.WriteString("    </tr>\n")
+	}
+	bw.WriteString("  </tbody>\n</table>\n")
+
+	return bw.Flush()
+}
+
+// ToLaTeX writes the DataFrame as a LaTeX tabular environment, with numeric
+// columns right aligned
+func (df *DataFrame) ToLaTeX(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	colNames := df.columns.Keys()
+	maxWidths := df.columnWidths(colNames, latexCell)
+
+	// Build the column specification from the schema
+	spec := "r"
+	for _, name := range colNames {
+		if df.isNumeric(name) {
+			spec += "r"
+		} else {
+			spec += "l"
+		}
+	}
+	fmt.Fprintf(bw, "\\begin{tabular}{%s}\n\\hline\n", spec)
+
+	// Write headers
+	fmt.Fprintf(bw, "%-*s", maxWidths[0], "Index")
+	for i, name := range colNames {
+		fmt.Fprintf(bw, " & %-*s", maxWidths[i+1], latexCell(name))
+	}
+	bw.WriteString(" \\\\\n\\hline\n")
+
+	// Write data rows
+	for row := 0; row < df.numRows; row++ {
+		fmt.Fprintf(bw, "%-*d", maxWidths[0], row)
+		for i, val := range df.GetRow(row) {
+			fmt.Fprintf(bw, " & %-*s", maxWidths[i+1], latexCell(val))
+		}
+		bw.WriteString(" \\\\\n")
+	}
+	bw.WriteString("\\hline\n\\end{tabular}\n")
+
+	return bw.Flush()
+}
+
+// isNumeric reports whether the named column holds numbers
+func (df *DataFrame) isNumeric(name string) bool {
+	return series.IsNumericDatatype(df.schema[name]) || series.IsDecimalDatatype(df.schema[name])
+}
+
+// cellText formats a value for a rendered table, leaving nil values empty
+func cellText(val interface{}) string {
+	if val == nil {
+		return ""
+	}
+	return series.FormatValue(val)
+}
+
+// markdownEscaper escapes the characters that would break a Markdown table cell
+var markdownEscaper = strings.NewReplacer(
+	"|", `\|`,
+	"<", "&lt;",
+	">", "&gt;",
+	"\n", "<br>",
+)
+
+// markdownCell formats a value for a Markdown table cell
+func markdownCell(val interface{}) string {
+	return markdownEscaper.Replace(cellText(val))
+}
+
+// latexEscaper escapes the characters that have a special meaning in LaTeX
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+)
+
+// latexCell formats a value for a LaTeX table cell
+func latexCell(val interface{}) string {
+	return latexEscaper.Replace(cellText(val))
+}
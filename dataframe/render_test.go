@@ -0,0 +1,76 @@
+package dataframe
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	df := newFrame(t,
+		"name", "string", []interface{}{"a|b", nil},
+		"n", "int", []interface{}{1, 200},
+	)
+	special := newFrame(t,
+		"x_y", "string", []interface{}{"50%", "<&>"},
+		"v", "float", []interface{}{1.5, nil},
+	)
+	tests := []struct {
+		name   string
+		render func(*DataFrame, io.Writer) error
+		df     *DataFrame
+		want   string
+	}{
+		{
+			name:   "markdown",
+			render: (*DataFrame).ToMarkdown,
+			df:     df,
+			want: "| Index | name | n   |\n" +
+				"| ----: | ---- | --: |\n" +
+				"|     0 | a\\|b |   1 |\n" +
+				"|     1 |      | 200 |\n",
+		},
+		{
+			name:   "markdown escapes html",
+			render: (*DataFrame).ToMarkdown,
+			df:     newFrame(t, "s", "string", []interface{}{"<b>\nx"}),
+			want: "| Index | s              |\n" +
+				"| ----: | -------------- |\n" +
+				"|     0 | &lt;b&gt;<br>x |\n",
+		},
+		{
+			name:   "html",
+			render: (*DataFrame).ToHTML,
+			df:     special,
+			want: "<table>\n" +
+				"  <thead>\n    <tr>\n      <th>Index</th>\n      <th>x_y</th>\n      <th>v</th>\n    </tr>\n" +
+				"    <tr>\n      <th></th>\n      <th>string</th>\n      <th>float</th>\n    </tr>\n  </thead>\n" +
+				"  <tbody>\n" +
+				"    <tr>\n      <th>0</th>\n      <td>50%</td>\n      <td>1.5</td>\n    </tr>\n" +
+				"    <tr>\n      <th>1</th>\n      <td>&lt;&amp;&gt;</td>\n      <td></td>\n    </tr>\n" +
+				"  </tbody>\n</table>\n",
+		},
+		{
+			name:   "latex",
+			render: (*DataFrame).ToLaTeX,
+			df:     special,
+			want: "\\begin{tabular}{rlr}\n\\hline\n" +
+				"Index & x\\_y & v   \\\\\n\\hline\n" +
+				"0     & 50\\% & 1.5 \\\\\n" +
+				"1     & <\\&> &     \\\\\n" +
+				"\\hline\n\\end{tabular}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.render(tt.df, &buf); err != nil {
+				t.Fatalf("render: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
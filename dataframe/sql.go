@@ -0,0 +1,366 @@
+package dataframe
+
+import (
+	"database/sql"
+	"fmt"
+	"koalas/series"
+	"koalas/utils"
+	"reflect"
+	"strings"
+)
+
+// SQLWriteOptions configures how WriteSQL creates and fills a table
+type SQLWriteOptions struct {
+	IfExists    string            // What to do when the table exists: fail, replace or append
+	BatchSize   int               // Number of rows per INSERT statement, defaults to 500 and is capped at maxSQLParameters values
+	Placeholder string            // Bind parameter style: "?" or "$" for $1, $2, ...
+	Types       map[string]string // SQL column type for each Series datatype, decimals default to NUMERIC(p,s)
+}
+
+// maxSQLParameters is the most bind parameters one INSERT may use, the
+// SQLite limit, which is below PostgreSQL's
+const maxSQLParameters = 32766
+
+// DefaultSQLWriteOptions returns options that work with SQLite and PostgreSQL
+func DefaultSQLWriteOptions() SQLWriteOptions {
+	return SQLWriteOptions{
+		IfExists:    "fail",
+		BatchSize:   500,
+		Placeholder: "?",
+		Types: map[string]string{
+			"int":      "BIGINT",
+			"float":    "DOUBLE PRECISION",
+			"string":   "TEXT",
+			"bool":     "BOOLEAN",
+			"int8":     "SMALLINT",
+			"int16":    "SMALLINT",
+			"int32":    "INTEGER",
+			"int64":    "BIGINT",
+			"uint8":    "SMALLINT",
+			"uint16":   "INTEGER",
+			"uint32":   "BIGINT",
+			"uint64":   "NUMERIC(20)",
+			"float32":  "REAL",
+			"category": "TEXT",
+		},
+	}
+}
+
+// ReadSQL reads every row of a query result into a new DataFrame. The datatype
+// of each column comes from the driver's column types, falling back to the
+// scanned values when the driver does not report one. NULLs become nil.
+func ReadSQL(rows *sql.Rows) (*DataFrame, error) {
+	defer rows.Close()
+
+	colTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, fmt.Errorf("error reading column types: %v", err)
+	}
+
+	datatypes := make([]string, len(colTypes))
+	for i, ct := range colTypes {
+		datatypes[i] = sqlDatatype(ct)
+	}
+
+	// Scan every row into generic values
+	columns := make([][]interface{}, len(colTypes))
+	dest := make([]interface{}, len(colTypes))
+	for rows.Next() {
+		values := make([]interface{}, len(colTypes))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("error scanning row: %v", err)
+		}
+		for i, v := range values {
+			columns[i] = append(columns[i], v)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading rows: %v", err)
+	}
+
+	seriesList := make([]*series.Series, len(colTypes))
+	for i, ct := range colTypes {
+		datatype := datatypes[i]
+		if datatype == "" {
+			datatype = inferSQLType(columns[i])
+		}
+
+		values := make([]interface{}, len(columns[i]))
+		for j, v := range columns[i] {
+			value, err := fromSQLValue(v, datatype)
+			if err != nil {
+				return nil, fmt.Errorf("column %s, row %d: %v", ct.Name(), j, err)
+			}
+			values[j] = value
+		}
+		seriesList[i] = newSeries(ct.Name(), datatype, values)
+	}
+
+	return Create(seriesList)
+}
+
+// WriteSQL creates a table from the DataFrame's schema and inserts every row
+// in batches inside a single transaction
+func (df *DataFrame) WriteSQL(db *sql.DB, table string, options SQLWriteOptions) error {
+	defaults := DefaultSQLWriteOptions()
+	if options.IfExists == "" {
+		options.IfExists = defaults.IfExists
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaults.BatchSize
+	}
+	if options.Placeholder == "" {
+		options.Placeholder = defaults.Placeholder
+	}
+	if options.Types == nil {
+		options.Types = defaults.Types
+	}
+	ifExistsModes := []string{"fail", "replace", "append"}
+	if !utils.StringContains(ifExistsModes, options.IfExists) {
+		return fmt.Errorf("invalid if exists mode: %s", options.IfExists)
+	}
+	if options.Placeholder != "?" && options.Placeholder != "$" {
+		return fmt.Errorf("invalid placeholder style: %s", options.Placeholder)
+	}
+
+	colNames := df.columns.Keys()
+	if len(colNames) == 0 {
+		return fmt.Errorf("cannot write a DataFrame without columns")
+	}
+
+	// Keep every statement under the bind parameter limit
+	if limit := maxSQLParameters / len(colNames); options.BatchSize > limit {
+		options.BatchSize = limit
+	}
+	if options.BatchSize < 1 {
+		return fmt.Errorf("cannot bind %d columns in one sql statement", len(colNames))
+	}
+
+	// Build the column definitions from the schema
+	definitions := make([]string, len(colNames))
+	quotedNames := make([]string, len(colNames))
+	for i, name := range colNames {
+		sqlType, exists := options.Types[df.schema[name]]
+		if precision, scale, ok := series.ParseDecimalType(df.schema[name]); ok && !exists {
+			sqlType, exists = fmt.Sprintf("NUMERIC(%d,%d)", precision, scale), true
+		}
+		if !exists {
+			return fmt.Errorf("no sql type for column %s of type %s", name, df.schema[name])
+		}
+		quotedNames[i] = quoteIdentifier(name)
+		definitions[i] = quotedNames[i] + " " + sqlType
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	switch options.IfExists {
+	case "replace":
+		if _, err := tx.Exec("DROP TABLE IF EXISTS " + quoteIdentifier(table)); err != nil {
+			return fmt.Errorf("error dropping table %s: %v", table, err)
+		}
+		fallthrough
+	case "fail":
+		create := fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdentifier(table), strings.Join(definitions, ", "))
+		if _, err := tx.Exec(create); err != nil {
+			return fmt.Errorf("error creating table %s: %v", table, err)
+		}
+	case "append":
+		create := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", quoteIdentifier(table), strings.Join(definitions, ", "))
+		if _, err := tx.Exec(create); err != nil {
+			return fmt.Errorf("error creating table %s: %v", table, err)
+		}
+	}
+
+	// Insert the rows in batches, each as one multi-row INSERT
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", quoteIdentifier(table), strings.Join(quotedNames, ", "))
+	for start := 0; start < df.numRows; start += options.BatchSize {
+		end := start + options.BatchSize
+		if end > df.numRows {
+			end = df.numRows
+		}
+
+		tuples := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*len(colNames))
+		for row := start; row < end; row++ {
+			marks := make([]string, len(colNames))
+			for i := range marks {
+				if options.Placeholder == "$" {
+					marks[i] = fmt.Sprintf("$%d", len(args)+i+1)
+				} else {
+					marks[i] = "?"
+				}
+			}
+			tuples = append(tuples, "("+strings.Join(marks, ", ")+")")
+			args = append(args, df.GetRow(row)...)
+		}
+
+		if _, err := tx.Exec(insert+strings.Join(tuples, ", "), args...); err != nil {
+			return fmt.Errorf("error inserting rows %d to %d: %v", start, end-1, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %v", err)
+	}
+	return nil
+}
+
+// sqlDatatype maps a driver column type to a Series datatype, or returns an
+// empty string when the driver does not say
+func sqlDatatype(ct *sql.ColumnType) string {
+	switch ct.ScanType() {
+	case reflect.TypeOf(int64(0)), reflect.TypeOf(int32(0)), reflect.TypeOf(int16(0)),
+		reflect.TypeOf(int8(0)), reflect.TypeOf(int(0)), reflect.TypeOf(uint32(0)),
+		reflect.TypeOf(uint16(0)), reflect.TypeOf(uint8(0)),
+		reflect.TypeOf(sql.NullInt64{}), reflect.TypeOf(sql.NullInt32{}),
+		reflect.TypeOf(sql.NullInt16{}), reflect.TypeOf(sql.NullByte{}):
+		return "int"
+	case reflect.TypeOf(float64(0)), reflect.TypeOf(float32(0)), reflect.TypeOf(sql.NullFloat64{}):
+		return "float"
+	case reflect.TypeOf(false), reflect.TypeOf(sql.NullBool{}):
+		return "bool"
+	case reflect.TypeOf(""), reflect.TypeOf(sql.NullString{}):
+		return "string"
+	case reflect.TypeOf(uint64(0)):
+		return "uint64"
+	}
+
+	// Fall back to the declared type name for drivers with dynamic scan types,
+	// matching the whole name so POINT or INTERVAL are not taken for INT
+	name := strings.ToUpper(strings.TrimSpace(ct.DatabaseTypeName()))
+	if i := strings.Index(name, "("); i >= 0 {
+		name = strings.TrimSpace(name[:i])
+	}
+	switch name {
+	case "DEC", "DECIMAL", "NUMERIC":
+		if precision, scale, ok := ct.DecimalSize(); ok && series.IsDecimalDatatype(series.DecimalType(int(precision), int(scale))) {
+			return series.DecimalType(int(precision), int(scale))
+		}
+	case "INT", "INTEGER", "TINYINT", "SMALLINT", "MEDIUMINT", "BIGINT", "INT2", "INT4", "INT8",
+		"SERIAL", "SMALLSERIAL", "BIGSERIAL":
+		return "int"
+	case "UNSIGNED BIG INT":
+		return "uint64"
+	case "BOOL", "BOOLEAN":
+		return "bool"
+	case "REAL", "FLOAT", "FLOAT4", "FLOAT8", "DOUBLE", "DOUBLE PRECISION":
+		return "float"
+	case "CHAR", "CHARACTER", "VARCHAR", "CHARACTER VARYING", "VARYING CHARACTER", "NCHAR",
+		"NVARCHAR", "NATIVE CHARACTER", "TEXT", "CLOB":
+		return "string"
+	}
+	return ""
+}
+
+// inferSQLType picks a datatype from the scanned values of a column
+func inferSQLType(values []interface{}) string {
+	datatype := ""
+	for _, v := range values {
+		var current string
+		switch v.(type) {
+		case nil:
+			continue
+		case int64, int32, int16, int8, int, uint64:
+			current = "int"
+		case float64, float32:
+			current = "float"
+		case bool:
+			current = "bool"
+		default:
+			return "string"
+		}
+		if datatype == "" || (datatype == "int" && current == "float") {
+			datatype = current
+		} else if datatype != current && !(datatype == "float" && current == "int") {
+			return "string"
+		}
+	}
+	if datatype == "" {
+		return "string"
+	}
+	return datatype
+}
+
+// fromSQLValue converts a scanned value to the given datatype
+func fromSQLValue(v interface{}, datatype string) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if b, ok := v.([]byte); ok {
+		if datatype == "string" {
+			return string(b), nil
+		}
+		return parseValue(string(b), datatype, nil)
+	}
+
+	switch datatype {
+	case "int":
+		switch n := v.(type) {
+		case int64:
+			return int(n), nil
+		case int32:
+			return int(n), nil
+		case int16:
+			return int(n), nil
+		case int8:
+			return int(n), nil
+		case int:
+			return n, nil
+		case uint32:
+			return int(n), nil
+		case uint16:
+			return int(n), nil
+		case uint8:
+			return int(n), nil
+		case uint64:
+			return series.ConvertValue(n, datatype)
+		case bool:
+			if n {
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case "float":
+		switch n := v.(type) {
+		case float64:
+			return n, nil
+		case float32:
+			return float64(n), nil
+		case int64:
+			return float64(n), nil
+		case uint64:
+			return float64(n), nil
+		}
+	case "bool":
+		switch n := v.(type) {
+		case bool:
+			return n, nil
+		case int64:
+			return n != 0, nil
+		}
+	case "string":
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		return fmt.Sprintf("%v", v), nil
+	}
+	if s, ok := v.(string); ok {
+		return parseValue(s, datatype, nil)
+	}
+	if series.IsDecimalDatatype(datatype) || series.IsNumericDatatype(datatype) {
+		return series.ConvertValue(v, datatype)
+	}
+	return nil, fmt.Errorf("invalid type: expected %s, got %T", datatype, v)
+}
+
+// quoteIdentifier quotes a table or column name using ANSI double quotes
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,215 @@
+package dataframe
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+// openSQLite opens a fresh in-memory SQLite database
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	// Every connection to :memory: is its own database
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSQLRoundTrip(t *testing.T) {
+	db := openSQLite(t)
+	df := newFrame(t,
+		"n", "int", []interface{}{1, nil, 3},
+		"x", "float", []interface{}{1.5, 2.5, nil},
+		"s", "string", []interface{}{"a", nil, "c"},
+	)
+	if err := df.WriteSQL(db, "t", DefaultSQLWriteOptions()); err != nil {
+		t.Fatalf("WriteSQL: %v", err)
+	}
+	rows, err := db.Query(`SELECT n, x, s FROM t`)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	back, err := ReadSQL(rows)
+	if err != nil {
+		t.Fatalf("ReadSQL: %v", err)
+	}
+	checkColumn(t, back, "n", "int", []interface{}{1, nil, 3})
+	checkColumn(t, back, "x", "float", []interface{}{1.5, 2.5, nil})
+	checkColumn(t, back, "s", "string", []interface{}{"a", nil, "c"})
+}
+
+func TestWriteSQLIfExists(t *testing.T) {
+	db := openSQLite(t)
+	df := newFrame(t, "n", "int", []interface{}{1, 2})
+	if err := df.WriteSQL(db, "t", DefaultSQLWriteOptions()); err != nil {
+		t.Fatalf("WriteSQL: %v", err)
+	}
+
+	tests := []struct {
+		ifExists string
+		wantErr  bool
+		wantRows int
+	}{
+		{"fail", true, 2},
+		{"append", false, 4},
+		{"replace", false, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ifExists, func(t *testing.T) {
+			options := DefaultSQLWriteOptions()
+			options.IfExists = tt.ifExists
+			if err := df.WriteSQL(db, "t", options); (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			var count int
+			if err := db.QueryRow(`SELECT COUNT(*) FROM t`).Scan(&count); err != nil {
+				t.Fatalf("count: %v", err)
+			}
+			if count != tt.wantRows {
+				t.Errorf("got %d rows, want %d", count, tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestWriteSQLManyColumns(t *testing.T) {
+	// 100 columns of 400 rows bind 40000 values with the default batch size,
+	// more than SQLite allows in one statement
+	db := openSQLite(t)
+	var args []interface{}
+	values := make([]interface{}, 400)
+	for i := range values {
+		values[i] = i
+	}
+	for i := 0; i < 100; i++ {
+		args = append(args, fmt.Sprintf("c%d", i), "int", values)
+	}
+	df := newFrame(t, args...)
+	if err := df.WriteSQL(db, "wide", DefaultSQLWriteOptions()); err != nil {
+		t.Fatalf("WriteSQL: %v", err)
+	}
+	var count, sum int
+	if err := db.QueryRow(`SELECT COUNT(*), SUM(c99) FROM wide`).Scan(&count, &sum); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if count != 400 || sum != 399*400/2 {
+		t.Errorf("got %d rows summing to %d", count, sum)
+	}
+}
+
+func TestReadSQLDeclaredTypes(t *testing.T) {
+	db := openSQLite(t)
+	_, err := db.Exec(`CREATE TABLE shapes (id INTEGER, p POINT, span INTERVAL, name VARCHAR(10), ok BOOLEAN, big UNSIGNED BIG INT)`)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	_, err = db.Exec(`INSERT INTO shapes VALUES (1, 'p1', '1 day', 'a', 1, 7), (2, NULL, NULL, NULL, 0, NULL)`)
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	rows, err := db.Query(`SELECT id, p, span, name, ok, big FROM shapes`)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	df, err := ReadSQL(rows)
+	if err != nil {
+		t.Fatalf("ReadSQL: %v", err)
+	}
+	checkColumn(t, df, "id", "int", []interface{}{1, 2})
+	checkColumn(t, df, "p", "string", []interface{}{"p1", nil})
+	checkColumn(t, df, "span", "string", []interface{}{"1 day", nil})
+	checkColumn(t, df, "name", "string", []interface{}{"a", nil})
+	checkColumn(t, df, "ok", "bool", []interface{}{true, false})
+	// SQLite scans unsigned columns as int64
+	checkColumn(t, df, "big", "int", []interface{}{7, nil})
+}
+
+func TestReadSQLTypeNames(t *testing.T) {
+	// Without rows the driver gives no scan types, so the declared names decide
+	db := openSQLite(t)
+	_, err := db.Exec(`CREATE TABLE shapes (id BIGINT, p POINT, span INTERVAL, name VARCHAR(10), ok BOOLEAN, x DOUBLE PRECISION, big UNSIGNED BIG INT)`)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	rows, err := db.Query(`SELECT * FROM shapes`)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	df, err := ReadSQL(rows)
+	if err != nil {
+		t.Fatalf("ReadSQL: %v", err)
+	}
+	want := map[string]string{
+		"id": "int", "p": "string", "span": "string", "name": "string",
+		"ok": "bool", "x": "float", "big": "uint64",
+	}
+	for name, datatype := range want {
+		if got := df.schema[name]; got != datatype {
+			t.Errorf("column %s: got %s, want %s", name, got, datatype)
+		}
+	}
+}
+
+func TestFromSQLValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		datatype string
+		want     interface{}
+		wantErr  bool
+	}{
+		{"nil", nil, "int", nil, false},
+		{"bytes as string", []byte("x"), "string", "x", false},
+		{"bytes as int", []byte("12"), "int", 12, false},
+		{"uint64 as int", uint64(5), "int", 5, false},
+		{"uint64 overflowing int", uint64(1 << 63), "int", nil, true},
+		{"uint64 as uint64", uint64(1 << 63), "uint64", uint64(1 << 63), false},
+		{"uint64 as float", uint64(2), "float", 2.0, false},
+		{"int64 as uint8", int64(200), "uint8", uint8(200), false},
+		{"int64 overflowing uint8", int64(300), "uint8", nil, true},
+		{"bool from int", int64(1), "bool", true, false},
+		{"invalid", 1.5, "bool", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fromSQLValue(tt.value, tt.datatype)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteSQLErrors(t *testing.T) {
+	db := openSQLite(t)
+	df := newFrame(t, "n", "int", []interface{}{1})
+	tests := []struct {
+		name    string
+		df      *DataFrame
+		options SQLWriteOptions
+		want    string
+	}{
+		{"if exists", df, SQLWriteOptions{IfExists: "truncate"}, "invalid if exists mode"},
+		{"placeholder", df, SQLWriteOptions{Placeholder: ":"}, "invalid placeholder style"},
+		{"no columns", &DataFrame{columns: NewOrderedMap()}, DefaultSQLWriteOptions(), "without columns"},
+		{"no sql type", df, SQLWriteOptions{Types: map[string]string{}}, "no sql type for column n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.df.WriteSQL(db, "t", tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
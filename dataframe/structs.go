@@ -0,0 +1,344 @@
+package dataframe
+
+import (
+	"fmt"
+	"koalas/series"
+	"reflect"
+	"strings"
+)
+
+// structField describes how one struct field maps to a column
+type structField struct {
+	index    []int
+	name     string
+	datatype string
+}
+
+// FromStructs builds a DataFrame from a slice of structs or struct pointers.
+// Each exported field becomes a column, named and typed by an optional
+// `koalas:"name,type"` tag. Pointer fields are nullable and nil pointers
+// become nil values. A numeric field may be tagged with any numeric datatype,
+// and its values are range checked against it. A tag of "-" skips the field.
+func FromStructs(slice any) (*DataFrame, error) {
+	v := reflect.ValueOf(slice)
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("expected a slice of structs, got %T", slice)
+	}
+
+	elemType := v.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a slice of structs, got %T", slice)
+	}
+
+	fields, err := structFields(elemType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect the values of each field into its column
+	columns := make([][]interface{}, len(fields))
+	for i := range columns {
+		columns[i] = make([]interface{}, v.Len())
+	}
+	for row := 0; row < v.Len(); row++ {
+		elem := v.Index(row)
+		if isPtr {
+			if elem.IsNil() {
+				return nil, fmt.Errorf("row %d: nil struct pointer", row)
+			}
+			elem = elem.Elem()
+		}
+		for i, field := range fields {
+			value, err := fromStructValue(elem.FieldByIndex(field.index), field.datatype)
+			if err != nil {
+				return nil, fmt.Errorf("row %d, field %s: %v", row, field.name, err)
+			}
+			columns[i][row] = value
+		}
+	}
+
+	seriesList := make([]*series.Series, len(fields))
+	for i, field := range fields {
+		seriesList[i] = newSeries(field.name, field.datatype, columns[i])
+	}
+	return Create(seriesList)
+}
+
+// ToStructs decodes every row of the DataFrame into a pointer to a slice of
+// structs or struct pointers, matching columns to fields by their koalas tag
+// or field name. Columns without a matching field are ignored.
+func (df *DataFrame) ToStructs(ptrToSlice any) error {
+	ptr := reflect.ValueOf(ptrToSlice)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("expected a pointer to a slice of structs, got %T", ptrToSlice)
+	}
+
+	sliceValue := ptr.Elem()
+	elemType := sliceValue.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("expected a pointer to a slice of structs, got %T", ptrToSlice)
+	}
+
+	fields, err := structFields(elemType)
+	if err != nil {
+		return err
+	}
+
+	// Every field needs a column of a compatible type
+	columns := make([]*series.Series, len(fields))
+	for i, field := range fields {
+		col, exists := df.columns.Get(field.name)
+		if !exists {
+			return fmt.Errorf("column '%s' does not exist in DataFrame", field.name)
+		}
+		// Category columns fill string fields with their labels
+		if col.Datatype == "category" && field.datatype == "string" {
+			if col, err = col.Cat().AsString(); err != nil {
+				return err
+			}
+		}
+		// Numeric values are range checked as they are stored
+		numeric := series.IsNumericDatatype(col.Datatype) && series.IsNumericDatatype(field.datatype)
+		if col.Datatype != field.datatype && !numeric {
+			return fmt.Errorf("type mismatch for column %s: field has type %s, column has type %s",
+				field.name, field.datatype, col.Datatype)
+		}
+		columns[i] = col
+	}
+
+	result := reflect.MakeSlice(sliceValue.Type(), df.numRows, df.numRows)
+	for row := 0; row < df.numRows; row++ {
+		elem := reflect.New(elemType).Elem()
+		for i, field := range fields {
+			value, err := columns[i].Get(row)
+			if err != nil {
+				return err
+			}
+			if err := setStructValue(elem.FieldByIndex(field.index), value); err != nil {
+				return fmt.Errorf("row %d, column %s: %v", row, field.name, err)
+			}
+		}
+		if isPtr {
+			result.Index(row).Set(elem.Addr())
+		} else {
+			result.Index(row).Set(elem)
+		}
+	}
+
+	sliceValue.Set(result)
+	return nil
+}
+
+// structFields reads the column mapping of every exported field of a struct type
+func structFields(t reflect.Type) ([]structField, error) {
+	fields := []structField{}
+	seen := make(map[string]bool)
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+
+		name, datatype := f.Name, ""
+		if tag, ok := f.Tag.Lookup("koalas"); ok {
+			if tag == "-" {
+				continue
+			}
+			tagName, tagType, _ := strings.Cut(tag, ",")
+			if tagName != "" {
+				name = tagName
+			}
+			datatype = tagType
+		}
+
+		fieldType := f.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		kindType := structDatatype(fieldType)
+		if kindType == "" {
+			return nil, fmt.Errorf("field %s has unsupported type %s", f.Name, f.Type)
+		}
+		if datatype == "" {
+			datatype = kindType
+		} else if !series.IsValidDatatype(datatype) {
+			return nil, fmt.Errorf("invalid type for field %s: %s", f.Name, datatype)
+		} else if datatype != kindType && !(series.IsNumericDatatype(datatype) && series.IsNumericDatatype(kindType)) {
+			return nil, fmt.Errorf("type mismatch for field %s: tag says %s, field has type %s", f.Name, datatype, f.Type)
+		}
+
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate column name: %s", name)
+		}
+		seen[name] = true
+
+		fields = append(fields, structField{
+			index:    f.Index,
+			name:     name,
+			datatype: datatype,
+		})
+	}
+	return fields, nil
+}
+
+// structDatatype returns the Series datatype that holds a Go type, or an
+// empty string when there is none. Sized numbers keep their width, while int
+// and float64 give "int" and "float" and uint gives "uint64".
+func structDatatype(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int:
+		return "int"
+	case reflect.Int8:
+		return "int8"
+	case reflect.Int16:
+		return "int16"
+	case reflect.Int32:
+		return "int32"
+	case reflect.Int64:
+		return "int64"
+	case reflect.Uint8:
+		return "uint8"
+	case reflect.Uint16:
+		return "uint16"
+	case reflect.Uint32:
+		return "uint32"
+	case reflect.Uint, reflect.Uint64:
+		return "uint64"
+	case reflect.Float32:
+		return "float32"
+	case reflect.Float64:
+		return "float"
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	}
+	return ""
+}
+
+// fromStructValue converts a struct field value into a Series value
+func fromStructValue(v reflect.Value, datatype string) (interface{}, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	if series.IsNumericDatatype(datatype) {
+		var value interface{}
+		switch {
+		case v.CanInt():
+			value = v.Int()
+		case v.CanUint():
+			value = v.Uint()
+		default:
+			value = v.Float()
+		}
+		return series.ConvertValue(value, datatype)
+	}
+
+	switch datatype {
+	case "string":
+		return v.String(), nil
+	case "bool":
+		return v.Bool(), nil
+	}
+	return nil, fmt.Errorf("invalid type: %s", datatype)
+}
+
+// setStructNumber stores a value of one of the sized numeric datatypes into a
+// numeric field, checking that it fits
+func setStructNumber(target reflect.Value, value interface{}) error {
+	switch {
+	case target.CanInt():
+		v, err := series.ConvertValue(value, "int64")
+		if err != nil || target.OverflowInt(v.(int64)) {
+			return fmt.Errorf("value %v overflows field of type %s", value, target.Type())
+		}
+		target.SetInt(v.(int64))
+	case target.CanUint():
+		v, err := series.ConvertValue(value, "uint64")
+		if err != nil || target.OverflowUint(v.(uint64)) {
+			return fmt.Errorf("value %v overflows field of type %s", value, target.Type())
+		}
+		target.SetUint(v.(uint64))
+	case target.CanFloat():
+		v, _ := series.ConvertValue(value, "float")
+		target.SetFloat(v.(float64))
+	default:
+		return fmt.Errorf("cannot assign %T to field of type %s", value, target.Type())
+	}
+	return nil
+}
+
+// setStructValue stores a Series value into a struct field, allocating the
+// pointer for nullable fields
+func setStructValue(field reflect.Value, value interface{}) error {
+	if value == nil {
+		if field.Kind() != reflect.Ptr {
+			return fmt.Errorf("cannot assign nil to field of type %s", field.Type())
+		}
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	target := field
+	if field.Kind() == reflect.Ptr {
+		target = reflect.New(field.Type().Elem()).Elem()
+	}
+
+	switch v := value.(type) {
+	case int:
+		if target.CanInt() {
+			if target.OverflowInt(int64(v)) {
+				return fmt.Errorf("value %d overflows field of type %s", v, target.Type())
+			}
+			target.SetInt(int64(v))
+		} else if target.CanUint() {
+			if v < 0 || target.OverflowUint(uint64(v)) {
+				return fmt.Errorf("value %d overflows field of type %s", v, target.Type())
+			}
+			target.SetUint(uint64(v))
+		} else if target.CanFloat() {
+			target.SetFloat(float64(v))
+		} else {
+			return fmt.Errorf("cannot assign int to field of type %s", target.Type())
+		}
+	case float64:
+		if target.CanFloat() {
+			target.SetFloat(v)
+		} else if err := setStructNumber(target, v); err != nil {
+			return err
+		}
+	case string:
+		if target.Kind() != reflect.String {
+			return fmt.Errorf("cannot assign string to field of type %s", target.Type())
+		}
+		target.SetString(v)
+	case bool:
+		if target.Kind() != reflect.Bool {
+			return fmt.Errorf("cannot assign bool to field of type %s", target.Type())
+		}
+		target.SetBool(v)
+	case int8, int16, int32, int64, uint8, uint16, uint32, uint64, float32:
+		if err := setStructNumber(target, v); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("cannot assign %T to field of type %s", value, target.Type())
+	}
+
+	if field.Kind() == reflect.Ptr {
+		field.Set(target.Addr())
+	}
+	return nil
+}
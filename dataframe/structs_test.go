@@ -0,0 +1,270 @@
+package dataframe
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type person struct {
+	Name    string   `koalas:"name"`
+	Age     int      `koalas:"age,int"`
+	Score   *float64 `koalas:"score"`
+	Active  bool
+	Visits  uint64
+	private int
+	Skipped string `koalas:"-"`
+}
+
+func TestFromStructs(t *testing.T) {
+	score := 9.5
+	people := []person{
+		{Name: "ann", Age: 30, Score: &score, Active: true, Visits: 3},
+		{Name: "bob", Age: 25, Active: false, Visits: 1 << 63},
+	}
+	for _, input := range []any{people, []*person{&people[0], &people[1]}} {
+		df, err := FromStructs(input)
+		if err != nil {
+			t.Fatalf("FromStructs(%T): %v", input, err)
+		}
+		if got := df.columns.Keys(); !reflect.DeepEqual(got, []string{"name", "age", "score", "Active", "Visits"}) {
+			t.Errorf("columns: got %v", got)
+		}
+		checkColumn(t, df, "name", "string", []interface{}{"ann", "bob"})
+		checkColumn(t, df, "age", "int", []interface{}{30, 25})
+		checkColumn(t, df, "score", "float", []interface{}{9.5, nil})
+		checkColumn(t, df, "Active", "bool", []interface{}{true, false})
+		checkColumn(t, df, "Visits", "uint64", []interface{}{uint64(3), uint64(1 << 63)})
+	}
+}
+
+func TestToStructs(t *testing.T) {
+	df := newFrame(t,
+		"name", "string", []interface{}{"ann", "bob"},
+		"age", "int", []interface{}{30, 25},
+		"score", "float", []interface{}{9.5, nil},
+		"Active", "bool", []interface{}{true, false},
+		"Visits", "uint64", []interface{}{uint64(3), uint64(4)},
+		"extra", "string", []interface{}{"x", "y"},
+	)
+	var people []person
+	if err := df.ToStructs(&people); err != nil {
+		t.Fatalf("ToStructs: %v", err)
+	}
+	if len(people) != 2 || people[0].Name != "ann" || people[1].Age != 25 || people[1].Visits != 4 {
+		t.Errorf("got %+v", people)
+	}
+	if people[0].Score == nil || *people[0].Score != 9.5 || people[1].Score != nil {
+		t.Errorf("scores: got %v, %v", people[0].Score, people[1].Score)
+	}
+
+	var pointers []*person
+	if err := df.ToStructs(&pointers); err != nil {
+		t.Fatalf("ToStructs: %v", err)
+	}
+	if len(pointers) != 2 || pointers[0].Name != "ann" || !pointers[0].Active {
+		t.Errorf("got %+v", pointers)
+	}
+}
+
+func TestToStructsRangeChecks(t *testing.T) {
+	type small struct {
+		N int8  `koalas:"n"`
+		U uint8 `koalas:"u"`
+	}
+	tests := []struct {
+		name    string
+		df      *DataFrame
+		want    small
+		wantErr string
+	}{
+		{
+			name: "fits",
+			df:   newFrame(t, "n", "int", []interface{}{-5}, "u", "int64", []interface{}{int64(200)}),
+			want: small{N: -5, U: 200},
+		},
+		{
+			name:    "int overflows int8",
+			df:      newFrame(t, "n", "int", []interface{}{300}, "u", "int", []interface{}{1}),
+			wantErr: "row 0, column n: value 300 overflows",
+		},
+		{
+			name:    "negative into uint8",
+			df:      newFrame(t, "n", "int", []interface{}{1}, "u", "int", []interface{}{-1}),
+			wantErr: "row 0, column u: value -1 overflows",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []small
+			err := tt.df.ToStructs(&got)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ToStructs: %v", err)
+			}
+			if len(got) != 1 || got[0] != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromStructsSizedFields(t *testing.T) {
+	type sized struct {
+		I8  int8
+		I16 int16
+		I32 int32
+		I64 int64
+		U8  uint8
+		U16 uint16
+		U32 uint32
+		U   uint
+		F32 float32
+	}
+	in := []sized{
+		{I8: -8, I16: -16, I32: -32, I64: -64, U8: 8, U16: 16, U32: 32, U: 1, F32: 0.5},
+		{I8: 127, I16: 1 << 14, I32: 1 << 30, I64: 1 << 62, U8: 255, U16: 1 << 15, U32: 1 << 31, U: 2, F32: -2},
+	}
+	df, err := FromStructs(in)
+	if err != nil {
+		t.Fatalf("FromStructs: %v", err)
+	}
+	checkColumn(t, df, "I8", "int8", []interface{}{int8(-8), int8(127)})
+	checkColumn(t, df, "I16", "int16", []interface{}{int16(-16), int16(1 << 14)})
+	checkColumn(t, df, "I32", "int32", []interface{}{int32(-32), int32(1 << 30)})
+	checkColumn(t, df, "I64", "int64", []interface{}{int64(-64), int64(1 << 62)})
+	checkColumn(t, df, "U8", "uint8", []interface{}{uint8(8), uint8(255)})
+	checkColumn(t, df, "U16", "uint16", []interface{}{uint16(16), uint16(1 << 15)})
+	checkColumn(t, df, "U32", "uint32", []interface{}{uint32(32), uint32(1 << 31)})
+	checkColumn(t, df, "U", "uint64", []interface{}{uint64(1), uint64(2)})
+	checkColumn(t, df, "F32", "float32", []interface{}{float32(0.5), float32(-2)})
+
+	var back []sized
+	if err := df.ToStructs(&back); err != nil {
+		t.Fatalf("ToStructs: %v", err)
+	}
+	if !reflect.DeepEqual(back, in) {
+		t.Errorf("got %+v, want %+v", back, in)
+	}
+}
+
+func TestFromStructsSizedTags(t *testing.T) {
+	type sized struct {
+		Big   int64    `koalas:"big,int64"`
+		Small int      `koalas:"small,int8"`
+		Count *uint16  `koalas:"count,uint32"`
+		Ratio float64  `koalas:"ratio,float32"`
+		Whole *float32 `koalas:"whole,int"`
+	}
+	count, whole := uint16(7), float32(3)
+	df, err := FromStructs([]sized{
+		{Big: 1 << 40, Small: -3, Count: &count, Ratio: 0.5, Whole: &whole},
+		{Big: -1, Small: 100},
+	})
+	if err != nil {
+		t.Fatalf("FromStructs: %v", err)
+	}
+	checkColumn(t, df, "big", "int64", []interface{}{int64(1 << 40), int64(-1)})
+	checkColumn(t, df, "small", "int8", []interface{}{int8(-3), int8(100)})
+	checkColumn(t, df, "count", "uint32", []interface{}{uint32(7), nil})
+	checkColumn(t, df, "ratio", "float32", []interface{}{float32(0.5), float32(0)})
+	checkColumn(t, df, "whole", "int", []interface{}{3, nil})
+
+	var back []sized
+	if err := df.ToStructs(&back); err != nil {
+		t.Fatalf("ToStructs: %v", err)
+	}
+	if len(back) != 2 || back[0].Big != 1<<40 || back[1].Small != 100 || *back[0].Count != 7 || back[1].Count != nil {
+		t.Errorf("got %+v", back)
+	}
+
+	type narrow struct {
+		N int `koalas:"n,int8"`
+	}
+	type fraction struct {
+		X float64 `koalas:"x,int"`
+	}
+	tests := []struct {
+		name  string
+		input any
+		want  string
+	}{
+		{"overflows tag", []narrow{{N: 1}, {N: 300}}, "row 1, field n: value 300 overflows int8"},
+		{"fraction into int", []fraction{{X: 1.5}}, "row 0, field x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromStructs(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructsErrors(t *testing.T) {
+	type badTag struct {
+		N int `koalas:"n,string"`
+	}
+	type unknownTag struct {
+		N int `koalas:"n,huge"`
+	}
+	type duplicate struct {
+		A int `koalas:"x"`
+		B int `koalas:"x"`
+	}
+	type unsupported struct {
+		C chan int
+	}
+	type plain struct {
+		N int `koalas:"n"`
+	}
+
+	fromTests := []struct {
+		name  string
+		input any
+		want  string
+	}{
+		{"not a slice", person{}, "expected a slice of structs"},
+		{"slice of ints", []int{1}, "expected a slice of structs"},
+		{"tag mismatch", []badTag{{}}, "type mismatch for field N"},
+		{"unknown tag type", []unknownTag{{}}, "invalid type for field N"},
+		{"duplicate name", []duplicate{{}}, "duplicate column name: x"},
+		{"unsupported field", []unsupported{{}}, "unsupported type"},
+		{"nil pointer", []*plain{nil}, "row 0: nil struct pointer"},
+	}
+	for _, tt := range fromTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromStructs(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	var plains []plain
+	toTests := []struct {
+		name   string
+		df     *DataFrame
+		target any
+		want   string
+	}{
+		{"not a pointer", newFrame(t, "n", "int", []interface{}{1}), plains, "expected a pointer to a slice"},
+		{"missing column", newFrame(t, "m", "int", []interface{}{1}), &plains, "column 'n' does not exist"},
+		{"type mismatch", newFrame(t, "n", "string", []interface{}{"x"}), &plains, "type mismatch for column n"},
+		{"nil into value field", newFrame(t, "n", "int", []interface{}{nil}), &plains, "cannot assign nil"},
+	}
+	for _, tt := range toTests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.df.ToStructs(tt.target)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
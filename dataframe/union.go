@@ -6,7 +6,9 @@ import (
 	"koalas/utils"
 )
 
-// Union combines two DataFrames
+// Union combines two DataFrames. Columns must match by name and position, and
+// their datatypes are promoted with series.PromoteType. A column holding only
+// nil values takes the datatype of the other DataFrame.
 func (df *DataFrame) Union(other *DataFrame) (*DataFrame, error) {
 	// Check if number of columns match
 	if df.numCols != other.numCols {
@@ -36,16 +38,30 @@ func (df *DataFrame) Union(other *DataFrame) (*DataFrame, error) {
 	// Compare columns using Zip
 	colCheck := utils.Zip(cols1, cols2)
 
-	// Check each column pair for name and data type match
+	// Check each column pair for name and data type match, numeric types are promoted
+	datatypes := make([]string, len(colCheck))
 	for i, pair := range colCheck {
 		if pair.First.Name != pair.Second.Name {
 			return nil, fmt.Errorf("column name mismatch at position %d: %s != %s",
 				i, pair.First.Name, pair.Second.Name)
 		}
-		if pair.First.DataType != pair.Second.DataType {
+		datatype, ok := series.PromoteType(pair.First.DataType, pair.Second.DataType)
+		if !ok {
+			// A column holding only nil takes the datatype of the other side
+			col1, _ := df.columns.Get(pair.First.Name)
+			col2, _ := other.columns.Get(pair.Second.Name)
+			switch {
+			case col1.NullCount() == col1.Len():
+				datatype, ok = pair.Second.DataType, true
+			case col2.NullCount() == col2.Len():
+				datatype, ok = pair.First.DataType, true
+			}
+		}
+		if !ok {
 			return nil, fmt.Errorf("data type mismatch for column %s: %s != %s",
 				pair.First.Name, pair.First.DataType, pair.Second.DataType)
 		}
+		datatypes[i] = datatype
 	}
 
 	// Create a new DataFrame to store the result
@@ -57,10 +73,26 @@ func (df *DataFrame) Union(other *DataFrame) (*DataFrame, error) {
 	}
 
 	// For each column, combine the data from both DataFrames
-	for _, name := range df.columns.Keys() {
-		// Get columns from both DataFrames
+	for i, name := range df.columns.Keys() {
+		// Get columns from both DataFrames, converted to the promoted type and
+		// with category columns sharing one dictionary
 		col1, _ := df.columns.Get(name)
 		col2, _ := other.columns.Get(name)
+		col1, err := convertSeries(col1, datatypes[i])
+		if err != nil {
+			return nil, err
+		}
+		col2, err = convertSeries(col2, datatypes[i])
+		if err != nil {
+			return nil, err
+		}
+		if datatypes[i] == "category" {
+			unified, err := series.UnifyCategories(col1, col2)
+			if err != nil {
+				return nil, err
+			}
+			col1, col2 = unified[0], unified[1]
+		}
 
 		// Pre-allocate the combined data slice with exact capacity
 		combinedData := make([]series.Entry, 0, df.numRows+other.numRows)
@@ -83,3 +115,25 @@ func (df *DataFrame) Union(other *DataFrame) (*DataFrame, error) {
 
 	return result, nil
 }
+
+// convertSeries returns the series with its values converted to a datatype
+// chosen by series.PromoteType. The series itself is returned when it already
+// has that datatype.
+func convertSeries(col *series.Series, datatype string) (*series.Series, error) {
+	if col.Datatype == datatype {
+		return col, nil
+	}
+	data := make([]series.Entry, len(col.Data))
+	for i, entry := range col.Data {
+		value, err := series.ConvertValue(entry.Value, datatype)
+		if err != nil {
+			return nil, fmt.Errorf("column %s, row %d: %v", col.Name, i, err)
+		}
+		data[i] = series.Entry{Value: value, Index: entry.Index}
+	}
+	return &series.Series{
+		Name:     col.Name,
+		Datatype: datatype,
+		Data:     data,
+	}, nil
+}
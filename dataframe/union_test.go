@@ -0,0 +1,46 @@
+package dataframe
+
+import (
+	"koalas/series"
+	"reflect"
+	"testing"
+)
+
+func TestUnionCategory(t *testing.T) {
+	top, err := Create([]*series.Series{newCategory(t, "k", []interface{}{"b", "a"}, "b", "a")})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	bottom, err := Create([]*series.Series{newCategory(t, "k", []interface{}{"c", nil, "a"})})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	labels, err := Create([]*series.Series{newSeries("k", "string", []interface{}{"d"})})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	combined, err := top.Union(bottom)
+	if err != nil {
+		t.Fatalf("Union: %v", err)
+	}
+	values, categories := categoryLabels(t, combined, "k")
+	if !reflect.DeepEqual(values, []interface{}{"b", "a", "c", nil, "a"}) {
+		t.Errorf("labels: got %v", values)
+	}
+	if !reflect.DeepEqual(categories, []string{"b", "a", "c"}) {
+		t.Errorf("categories: got %v", categories)
+	}
+	// Every value shares the dictionary of the column
+	col, _ := combined.columns.Get("k")
+	if col.Data[1].Value != col.Data[4].Value {
+		t.Error("equal labels should hold the same value")
+	}
+
+	// A category and a string column combine as strings
+	mixed, err := top.Union(labels)
+	if err != nil {
+		t.Fatalf("Union: %v", err)
+	}
+	checkColumn(t, mixed, "k", "string", []interface{}{"b", "a", "d"})
+}
@@ -0,0 +1,235 @@
+package series
+
+import (
+	"cmp"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Sum adds up the non-nil values of a numeric or duration series, and is nil
+// when every value is nil. Signed integers other than "int" sum to an int64,
+// unsigned integers to a uint64 and float32 to a float64, so that narrow types
+// do not overflow. A sum that overflows its type is an error. Decimal series
+// sum exactly to a Decimal of their scale. Other series sum to their own Go
+// type.
+func (s *Series) Sum() (interface{}, error) {
+	if !IsNumericDatatype(s.Datatype) && !IsDecimalDatatype(s.Datatype) && s.Datatype != "duration" {
+		return nil, fmt.Errorf("cannot sum a %s series", s.Datatype)
+	}
+	values := s.values()
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	if IsDecimalDatatype(s.Datatype) {
+		sum := values[0].(Decimal)
+		for _, v := range values[1:] {
+			var err error
+			if sum, err = sum.Add(v.(Decimal)); err != nil {
+				return nil, fmt.Errorf("cannot sum %s: %v", s.Name, err)
+			}
+		}
+		return sum, nil
+	}
+
+	if s.Datatype == "duration" {
+		var sum int64
+		for _, v := range values {
+			var ok bool
+			if sum, ok = addInt64(sum, int64(v.(time.Duration))); !ok {
+				return nil, fmt.Errorf("cannot sum %s: sum overflows duration", s.Name)
+			}
+		}
+		return time.Duration(sum), nil
+	}
+
+	var isum int64
+	var usum uint64
+	var fsum float64
+	for _, v := range values {
+		n, _ := toNumber(v)
+		switch n.kind {
+		case 'i':
+			var ok bool
+			if isum, ok = addInt64(isum, n.i); !ok {
+				return nil, fmt.Errorf("cannot sum %s: sum overflows int64", s.Name)
+			}
+		case 'u':
+			if usum += n.u; usum < n.u {
+				return nil, fmt.Errorf("cannot sum %s: sum overflows uint64", s.Name)
+			}
+		default:
+			fsum += n.f
+		}
+	}
+	switch {
+	case s.Datatype == "int":
+		sum, err := ConvertValue(isum, "int")
+		if err != nil {
+			return nil, fmt.Errorf("cannot sum %s: %v", s.Name, err)
+		}
+		return sum, nil
+	case signedBits[s.Datatype] > 0:
+		return isum, nil
+	case unsignedBits[s.Datatype] > 0:
+		return usum, nil
+	}
+	return fsum, nil
+}
+
+// addInt64 adds two int64 values, reporting false when the sum overflows
+func addInt64(a int64, b int64) (int64, bool) {
+	sum := a + b
+	return sum, (sum > a) == (b > 0)
+}
+
+// Mean averages the non-nil values of a numeric or duration series. Numeric
+// series give a float64 and duration series give a time.Duration. Decimal
+// series give a Decimal of their scale and duration means are exact to the
+// nanosecond, both rounded half to even. The result is nil when every value
+// is nil.
+func (s *Series) Mean() (interface{}, error) {
+	if !IsNumericDatatype(s.Datatype) && !IsDecimalDatatype(s.Datatype) && s.Datatype != "duration" {
+		return nil, fmt.Errorf("cannot average a %s series", s.Datatype)
+	}
+	values := s.values()
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	if IsDecimalDatatype(s.Datatype) {
+		// Sum the coefficients, which cannot overflow, and divide once
+		sum := new(big.Int)
+		for _, v := range values {
+			sum.Add(sum, v.(Decimal).Coefficient())
+		}
+		mean, err := roundQuo(sum, big.NewInt(int64(len(values))), RoundHalfEven)
+		if err != nil {
+			return nil, err
+		}
+		_, scale, _ := ParseDecimalType(s.Datatype)
+		d, err := NewDecimalFromBig(mean, scale)
+		if err != nil {
+			return nil, err
+		}
+		return d, nil
+	}
+
+	if s.Datatype == "duration" {
+		// Sum the nanoseconds exactly, as a float64 cannot hold every one of
+		// them past about 104 days, and divide once
+		sum := new(big.Int)
+		for _, v := range values {
+			sum.Add(sum, big.NewInt(int64(v.(time.Duration))))
+		}
+		mean, err := roundQuo(sum, big.NewInt(int64(len(values))), RoundHalfEven)
+		if err != nil {
+			return nil, err
+		}
+		return time.Duration(mean.Int64()), nil
+	}
+
+	// Sum as float64 so that long series cannot overflow
+	var sum float64
+	for _, v := range values {
+		n, _ := toNumber(v)
+		sum += n.float()
+	}
+	return sum / float64(len(values)), nil
+}
+
+// Min returns the smallest non-nil value, or nil when every value is nil
+func (s *Series) Min() (interface{}, error) {
+	return s.extreme(-1)
+}
+
+// Max returns the largest non-nil value, or nil when every value is nil
+func (s *Series) Max() (interface{}, error) {
+	return s.extreme(1)
+}
+
+// extreme returns the value that compares as sign against every other value
+func (s *Series) extreme(sign int) (interface{}, error) {
+	var result interface{}
+	for _, v := range s.values() {
+		if result == nil {
+			result = v
+			continue
+		}
+		order, err := Compare(v, result)
+		if err != nil {
+			return nil, err
+		}
+		if order == sign {
+			result = v
+		}
+	}
+	return result, nil
+}
+
+// values returns the non-nil values of the series, checking each one holds the
+// series datatype so that callers can use plain type assertions
+func (s *Series) values() []interface{} {
+	values := make([]interface{}, 0, len(s.Data))
+	for _, entry := range s.Data {
+		if entry.Value != nil && IsValidType(entry.Value, s.Datatype) {
+			values = append(values, entry.Value)
+		}
+	}
+	return values
+}
+
+// Compare orders two values of the same type, returning -1, 0 or 1. Nil sorts
+// before every other value, false before true, datetimes by instant, and
+// numbers of any width, decimals included, by value. Categories follow the
+// order of their dictionary.
+func Compare(a interface{}, b interface{}) (int, error) {
+	switch {
+	case a == nil && b == nil:
+		return 0, nil
+	case a == nil:
+		return -1, nil
+	case b == nil:
+		return 1, nil
+	}
+
+	if order, ok := compareDecimal(a, b); ok {
+		return order, nil
+	}
+	if order, ok := compareCategory(a, b); ok {
+		return order, nil
+	}
+	if na, ok := toNumber(a); ok {
+		if nb, ok := toNumber(b); ok {
+			return compareNumbers(na, nb), nil
+		}
+	}
+
+	switch a := a.(type) {
+	case string:
+		if b, ok := b.(string); ok {
+			return strings.Compare(a, b), nil
+		}
+	case bool:
+		if b, ok := b.(bool); ok {
+			switch {
+			case a == b:
+				return 0, nil
+			case b:
+				return -1, nil
+			}
+			return 1, nil
+		}
+	case time.Time:
+		if b, ok := b.(time.Time); ok {
+			return a.Compare(b), nil
+		}
+	case time.Duration:
+		if b, ok := b.(time.Duration); ok {
+			return cmp.Compare(a, b), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %T with %T", a, b)
+}
@@ -0,0 +1,56 @@
+package series
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSum(t *testing.T) {
+	tests := []struct {
+		name string
+		s    *Series
+		want interface{}
+	}{
+		{"int", mustCreate(t, "s", "int", []interface{}{1, nil, 2}), 3},
+		{"int8 sums to int64", mustCreate(t, "s", "int8", []interface{}{int8(100), int8(100)}), int64(200)},
+		{"uint8 sums to uint64", mustCreate(t, "s", "uint8", []interface{}{uint8(200), uint8(200)}), uint64(400)},
+		{"float32 sums to float64", mustCreate(t, "s", "float32", []interface{}{float32(0.5), float32(0.25)}), 0.75},
+		{"all nil", mustCreate(t, "s", "int", []interface{}{nil}), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.Sum()
+			if err != nil {
+				t.Fatalf("Sum: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestSumOverflow(t *testing.T) {
+	tests := []struct {
+		name string
+		s    *Series
+		want string
+	}{
+		{"int", mustCreate(t, "s", "int", []interface{}{math.MaxInt64, 1}), "cannot sum s: sum overflows int64"},
+		{"int64 negative", mustCreate(t, "s", "int64", []interface{}{int64(math.MinInt64), int64(-1)}), "overflows int64"},
+		{"uint64", mustCreate(t, "s", "uint64", []interface{}{uint64(math.MaxUint64), uint64(1)}), "overflows uint64"},
+		{"duration", mustCreate(t, "s", "duration", []interface{}{time.Duration(math.MaxInt64), time.Nanosecond}), "overflows duration"},
+		{"string", mustCreate(t, "s", "string", []interface{}{"x"}), "cannot sum a string series"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.s.Sum()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package series
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+)
+
+// Sub subtracts other from the series position by position. Subtracting two
+// datetime Series gives a duration Series, subtracting a duration Series from
+// a datetime Series gives a datetime Series, and duration Series subtract to
+// a duration Series. Numeric Series subtract in the datatype PromoteType picks
+// for the pair, except that unsigned integers give the next wider signed
+// datatype, int64 for uint64, as their difference can be negative. A result
+// that does not fit its datatype is an error. A decimal result gets one more
+// digit of precision so that the difference of two values always fits. A nil
+// on either side gives nil.
+func (s *Series) Sub(other *Series) (*Series, error) {
+	if len(s.Data) != len(other.Data) {
+		return nil, fmt.Errorf("series length mismatch: %d and %d", len(s.Data), len(other.Data))
+	}
+
+	var datatype string
+	switch {
+	case s.Datatype == "datetime" && other.Datatype == "datetime":
+		datatype = "duration"
+	case s.Datatype == "datetime" && other.Datatype == "duration":
+		datatype = "datetime"
+	case s.Datatype == "duration" && other.Datatype == "duration":
+		datatype = "duration"
+	case IsNumericDatatype(s.Datatype) && IsNumericDatatype(other.Datatype):
+		datatype, _ = PromoteType(s.Datatype, other.Datatype)
+		if datatype == "uint64" {
+			datatype = "int64"
+		} else if _, unsigned := unsignedBits[datatype]; unsigned {
+			datatype, _ = PromoteType(datatype, "int8")
+		}
+	case IsDecimalDatatype(s.Datatype) || IsDecimalDatatype(other.Datatype):
+		var ok bool
+		if datatype, ok = PromoteType(s.Datatype, other.Datatype); !ok {
+			return nil, fmt.Errorf("cannot subtract %s from %s", other.Datatype, s.Datatype)
+		}
+		if precision, scale, ok := ParseDecimalType(datatype); ok {
+			datatype = DecimalType(min(MaxDecimalPrecision, precision+1), scale)
+		}
+	default:
+		return nil, fmt.Errorf("cannot subtract %s from %s", other.Datatype, s.Datatype)
+	}
+
+	data := make([]Entry, len(s.Data))
+	for i, entry := range s.Data {
+		data[i].Index = entry.Index
+		a, b := entry.Value, other.Data[i].Value
+		if a == nil || b == nil {
+			continue
+		}
+
+		var value interface{}
+		var err error
+		switch {
+		case IsDecimalDatatype(datatype):
+			value, err = subDecimals(a, b, datatype)
+		case IsNumericDatatype(datatype):
+			value, err = subNumbers(a, b, datatype)
+		default:
+			value, err = subValues(a, b)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s at position %d: %v", s.Name, i, err)
+		}
+		data[i].Value = value
+	}
+
+	return &Series{
+		Name:     s.Name,
+		Datatype: datatype,
+		Data:     data,
+	}, nil
+}
+
+// subDecimals subtracts two values converted to a decimal datatype
+func subDecimals(a interface{}, b interface{}, datatype string) (interface{}, error) {
+	x, err := ConvertValue(a, datatype)
+	if err != nil {
+		return nil, err
+	}
+	y, err := ConvertValue(b, datatype)
+	if err != nil {
+		return nil, err
+	}
+	return x.(Decimal).Sub(y.(Decimal))
+}
+
+// subNumbers subtracts two numbers of any width, giving a value of the
+// datatype. Integers are subtracted exactly and a difference that does not
+// fit the datatype is an error.
+func subNumbers(a interface{}, b interface{}, datatype string) (interface{}, error) {
+	x, okA := toNumber(a)
+	y, okB := toNumber(b)
+	if !okA || !okB {
+		return nil, fmt.Errorf("cannot subtract %T from %T", b, a)
+	}
+
+	switch datatype {
+	case "float":
+		return x.float() - y.float(), nil
+	case "float32":
+		return float32(x.float()) - float32(y.float()), nil
+	}
+	if x.kind == 'f' || y.kind == 'f' {
+		return nil, fmt.Errorf("cannot subtract %T from %T as %s", b, a, datatype)
+	}
+
+	var diff int64
+	if x.kind == 'i' && y.kind == 'i' {
+		diff = x.i - y.i
+		if (diff < x.i) != (y.i > 0) {
+			return nil, fmt.Errorf("%v - %v overflows %s", a, b, datatype)
+		}
+	} else {
+		// An unsigned side can exceed int64, so subtract in big integers
+		exact := new(big.Int).Sub(x.bigInt(), y.bigInt())
+		if !exact.IsInt64() {
+			return nil, fmt.Errorf("%v - %v overflows %s", a, b, datatype)
+		}
+		diff = exact.Int64()
+	}
+
+	value, err := ConvertValue(diff, datatype)
+	if err != nil {
+		return nil, fmt.Errorf("%v - %v overflows %s", a, b, datatype)
+	}
+	return value, nil
+}
+
+// subValues subtracts two non-nil datetimes or durations, failing when the
+// difference does not fit a time.Duration
+func subValues(a interface{}, b interface{}) (interface{}, error) {
+	switch a := a.(type) {
+	case time.Duration:
+		if b, ok := b.(time.Duration); ok {
+			diff := a - b
+			if (diff < a) != (b > 0) {
+				return nil, fmt.Errorf("%v - %v overflows duration", a, b)
+			}
+			return diff, nil
+		}
+	case time.Time:
+		switch b := b.(type) {
+		case time.Time:
+			// Sub saturates at the largest duration instead of overflowing
+			diff := a.Sub(b)
+			if !b.Add(diff).Equal(a) {
+				return nil, fmt.Errorf("difference of %s and %s overflows duration",
+					a.Format(time.RFC3339Nano), b.Format(time.RFC3339Nano))
+			}
+			return diff, nil
+		case time.Duration:
+			if b == math.MinInt64 {
+				return nil, fmt.Errorf("cannot subtract %v from a datetime", b)
+			}
+			return a.Add(-b), nil
+		}
+	}
+	return nil, fmt.Errorf("cannot subtract %T from %T", b, a)
+}
@@ -0,0 +1,132 @@
+package series
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSubNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     *Series
+		datatype string
+		want     []interface{}
+	}{
+		{
+			name:     "int",
+			a:        mustCreate(t, "a", "int", []interface{}{5, nil, -3}),
+			b:        mustCreate(t, "b", "int", []interface{}{2, 1, nil}),
+			datatype: "int",
+			want:     []interface{}{3, nil, nil},
+		},
+		{
+			name:     "uint8 widens to int16",
+			a:        mustCreate(t, "a", "uint8", []interface{}{uint8(1), uint8(255)}),
+			b:        mustCreate(t, "b", "uint8", []interface{}{uint8(2), uint8(0)}),
+			datatype: "int16",
+			want:     []interface{}{int16(-1), int16(255)},
+		},
+		{
+			name:     "uint32 widens to int",
+			a:        mustCreate(t, "a", "uint32", []interface{}{uint32(0)}),
+			b:        mustCreate(t, "b", "uint32", []interface{}{uint32(math.MaxUint32)}),
+			datatype: "int",
+			want:     []interface{}{-math.MaxUint32},
+		},
+		{
+			name:     "uint64 gives int64",
+			a:        mustCreate(t, "a", "uint64", []interface{}{uint64(3), uint64(math.MaxUint64)}),
+			b:        mustCreate(t, "b", "uint64", []interface{}{uint64(10), uint64(math.MaxUint64 - 5)}),
+			datatype: "int64",
+			want:     []interface{}{int64(-7), int64(5)},
+		},
+		{
+			name:     "int8 and uint8",
+			a:        mustCreate(t, "a", "int8", []interface{}{int8(-128)}),
+			b:        mustCreate(t, "b", "uint8", []interface{}{uint8(255)}),
+			datatype: "int16",
+			want:     []interface{}{int16(-383)},
+		},
+		{
+			name:     "float32",
+			a:        mustCreate(t, "a", "float32", []interface{}{float32(1.5)}),
+			b:        mustCreate(t, "b", "float32", []interface{}{float32(0.25)}),
+			datatype: "float32",
+			want:     []interface{}{float32(1.25)},
+		},
+		{
+			name:     "int and float",
+			a:        mustCreate(t, "a", "int", []interface{}{1}),
+			b:        mustCreate(t, "b", "float", []interface{}{0.5}),
+			datatype: "float",
+			want:     []interface{}{0.5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.a.Sub(tt.b)
+			if err != nil {
+				t.Fatalf("Sub: %v", err)
+			}
+			checkSeries(t, got, tt.datatype, tt.want)
+		})
+	}
+}
+
+func TestSubOverflow(t *testing.T) {
+	when := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		a, b *Series
+		want string
+	}{
+		{
+			name: "int8",
+			a:    mustCreate(t, "a", "int8", []interface{}{int8(0), int8(-100)}),
+			b:    mustCreate(t, "b", "int8", []interface{}{int8(1), int8(100)}),
+			want: "a at position 1: -100 - 100 overflows int8",
+		},
+		{
+			name: "int64",
+			a:    mustCreate(t, "a", "int64", []interface{}{int64(math.MinInt64)}),
+			b:    mustCreate(t, "b", "int64", []interface{}{int64(1)}),
+			want: "overflows int64",
+		},
+		{
+			name: "uint64",
+			a:    mustCreate(t, "a", "uint64", []interface{}{uint64(math.MaxUint64)}),
+			b:    mustCreate(t, "b", "uint64", []interface{}{uint64(0)}),
+			want: "overflows int64",
+		},
+		{
+			name: "duration",
+			a:    mustCreate(t, "a", "duration", []interface{}{time.Duration(math.MinInt64)}),
+			b:    mustCreate(t, "b", "duration", []interface{}{time.Second}),
+			want: "overflows duration",
+		},
+		{
+			name: "datetime",
+			a:    mustCreate(t, "a", "datetime", []interface{}{when.AddDate(500, 0, 0)}),
+			b:    mustCreate(t, "b", "datetime", []interface{}{when}),
+			want: "overflows duration",
+		},
+		{
+			name: "mismatched types",
+			a:    mustCreate(t, "a", "string", []interface{}{"x"}),
+			b:    mustCreate(t, "b", "int", []interface{}{1}),
+			want: "cannot subtract int from string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.a.Sub(tt.b)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
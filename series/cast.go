@@ -0,0 +1,214 @@
+package series
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CastMode says what AsType does with a value it cannot convert
+type CastMode int
+
+const (
+	CastStrict  CastMode = iota // Fail on the first value that cannot be converted
+	CastLenient                 // Turn values that cannot be converted into nil
+)
+
+// CastError reports a value that AsType could not convert
+type CastError struct {
+	Column   string      // Name of the Series
+	Position int         // Position of the value in the Series
+	Value    interface{} // Value that could not be converted
+	Err      error       // Reason the conversion failed
+}
+
+// Error formats the column, position and reason of the failed conversion
+func (e CastError) Error() string {
+	return fmt.Sprintf("%s at position %d: %v", e.Column, e.Position, e.Err)
+}
+
+// AsType converts the series to another datatype. Numbers convert between
+// each other as ConvertValue does, so only values that fit are kept, true and
+// false are 1 and 0, and numbers other than zero are true. Strings are parsed
+// as the target datatype the way ReadCSV parses them: booleans with ParseBool,
+// datetimes with DefaultDatetimeLayouts, lists and structs from JSON. Every
+// value can be formatted as a string, datetimes as RFC 3339 and lists and
+// structs as JSON. Categories convert as their labels.
+//
+// In CastStrict mode the first value that cannot be converted fails the cast.
+// In CastLenient mode it becomes nil and is reported in the returned errors.
+// Nil values stay nil and each Entry keeps its Index.
+func (s *Series) AsType(datatype string, mode CastMode) (*Series, []CastError, error) {
+	if !IsValidDatatype(datatype) {
+		return nil, nil, fmt.Errorf("invalid type: %s", datatype)
+	}
+	if mode != CastStrict && mode != CastLenient {
+		return nil, nil, fmt.Errorf("invalid cast mode: %d", mode)
+	}
+
+	// Categories convert through their labels, and keep their dictionary when
+	// cast to category
+	if s.Datatype == "category" && datatype == "category" {
+		return &Series{Name: s.Name, Datatype: s.Datatype, Data: slices.Clone(s.Data)}, nil, nil
+	}
+	source := s
+	if s.Datatype == "category" {
+		var err error
+		if source, err = s.Cat().AsString(); err != nil {
+			return nil, nil, err
+		}
+	}
+	target := datatype
+	if datatype == "category" {
+		target = "string"
+	}
+
+	var report []CastError
+	data := make([]Entry, len(source.Data))
+	for i, entry := range source.Data {
+		data[i].Index = entry.Index
+		value, err := castValue(entry.Value, target)
+		if err != nil {
+			castErr := CastError{Column: s.Name, Position: i, Value: entry.Value, Err: err}
+			if mode == CastStrict {
+				return nil, nil, castErr
+			}
+			report = append(report, castErr)
+			continue
+		}
+		data[i].Value = value
+	}
+
+	result := &Series{
+		Name:     s.Name,
+		Datatype: target,
+		Data:     data,
+	}
+	if datatype == "category" {
+		var err error
+		if result, err = result.AsCategory(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return result, report, nil
+}
+
+// castValue converts one value to a datatype for AsType
+func castValue(value interface{}, datatype string) (interface{}, error) {
+	if value == nil || IsValidType(value, datatype) {
+		return value, nil
+	}
+
+	if datatype == "string" {
+		return castString(value), nil
+	}
+	if s, ok := value.(string); ok {
+		return parseCast(s, datatype)
+	}
+
+	// Booleans are numbers for casting purposes
+	if b, ok := value.(bool); ok {
+		if IsNumericDatatype(datatype) || IsDecimalDatatype(datatype) {
+			if b {
+				return ConvertValue(1, datatype)
+			}
+			return ConvertValue(0, datatype)
+		}
+	}
+	if datatype == "bool" {
+		if d, ok := value.(Decimal); ok {
+			return !d.IsZero(), nil
+		}
+		if n, ok := toNumber(value); ok {
+			return n.float() != 0, nil
+		}
+	}
+
+	if IsNumericDatatype(datatype) || IsDecimalDatatype(datatype) {
+		return ConvertValue(value, datatype)
+	}
+	return nil, fmt.Errorf("cannot cast %T to %s", value, datatype)
+}
+
+// castString formats a value as the string AsType gives it
+func castString(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	}
+	if isStruct(value) || isList(value) {
+		return formatJSON(value)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// ParseBool parses "true" or "false" in any case. Other spellings such as 1,
+// 0, yes or no are rejected, so that ReadCSV reads 0/1 columns as int.
+func ParseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return false, fmt.Errorf("cannot parse %q as bool", s)
+}
+
+// parseCast parses a string as a value of the datatype. Integers may be
+// written as whole floats, such as "3.0".
+func parseCast(s string, datatype string) (interface{}, error) {
+	text := strings.TrimSpace(s)
+	if IsListDatatype(datatype) || datatype == "struct" {
+		raw, err := DecodeJSONValue([]byte(text))
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %q as %s", s, datatype)
+		}
+		value, err := FromJSONValue(raw, datatype)
+		if err != nil || value == nil {
+			return nil, fmt.Errorf("cannot parse %q as %s", s, datatype)
+		}
+		return value, nil
+	}
+
+	switch datatype {
+	case "bool":
+		return ParseBool(text)
+	case "datetime":
+		return ParseDatetime(text, nil)
+	case "duration":
+		return ParseDuration(text)
+	case "float", "float32":
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %q as %s", s, datatype)
+		}
+		return ConvertValue(f, datatype)
+	}
+
+	if IsDecimalDatatype(datatype) {
+		d, err := ParseDecimal(text)
+		if err != nil {
+			return nil, err
+		}
+		return ConvertValue(d, datatype)
+	}
+	if IsNumericDatatype(datatype) {
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return ConvertValue(n, datatype)
+		}
+		if n, err := strconv.ParseUint(text, 10, 64); err == nil {
+			return ConvertValue(n, datatype)
+		}
+		if f, err := strconv.ParseFloat(text, 64); err == nil {
+			return ConvertValue(f, datatype)
+		}
+		return nil, fmt.Errorf("cannot parse %q as %s", s, datatype)
+	}
+	return nil, fmt.Errorf("cannot cast string to %s", datatype)
+}
@@ -0,0 +1,134 @@
+package series
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAsType(t *testing.T) {
+	when := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	list, _ := NewList("list<int>", []interface{}{1, 2})
+	tests := []struct {
+		name     string
+		s        *Series
+		datatype string
+		want     []interface{}
+	}{
+		{"int to float", mustCreate(t, "s", "int", []interface{}{1, nil}), "float", []interface{}{1.0, nil}},
+		{"float to int", mustCreate(t, "s", "float", []interface{}{2.0}), "int8", []interface{}{int8(2)}},
+		{"int to string", mustCreate(t, "s", "int", []interface{}{-4}), "string", []interface{}{"-4"}},
+		{"float to string", mustCreate(t, "s", "float", []interface{}{0.1}), "string", []interface{}{"0.1"}},
+		{"bool to int", mustCreate(t, "s", "bool", []interface{}{true, false}), "int", []interface{}{1, 0}},
+		{"int to bool", mustCreate(t, "s", "int", []interface{}{3, 0}), "bool", []interface{}{true, false}},
+		{"string to bool", mustCreate(t, "s", "string", []interface{}{"True", " false "}), "bool", []interface{}{true, false}},
+		{"string to int", mustCreate(t, "s", "string", []interface{}{"3.0", "-2"}), "int", []interface{}{3, -2}},
+		{"string to datetime", mustCreate(t, "s", "string", []interface{}{"2024-03-01T12:30:00Z"}), "datetime", []interface{}{when}},
+		{"datetime to string", mustCreate(t, "s", "datetime", []interface{}{when}), "string", []interface{}{"2024-03-01T12:30:00Z"}},
+		{"string to duration", mustCreate(t, "s", "string", []interface{}{"1m30s"}), "duration", []interface{}{90 * time.Second}},
+		{"list to string", mustCreate(t, "s", "list<int>", []interface{}{list}), "string", []interface{}{"[1,2]"}},
+		{"string to list", mustCreate(t, "s", "string", []interface{}{"[1, 2]"}), "list<int>", []interface{}{list}},
+		{"struct to string", mustCreate(t, "s", "struct", []interface{}{map[string]interface{}{"a": 1}}), "string", []interface{}{`{"a":1}`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, report, err := tt.s.AsType(tt.datatype, CastStrict)
+			if err != nil {
+				t.Fatalf("AsType: %v", err)
+			}
+			if len(report) != 0 {
+				t.Errorf("got report %v in strict mode", report)
+			}
+			checkSeries(t, got, tt.datatype, tt.want)
+		})
+	}
+}
+
+func TestAsTypeStrictAndLenient(t *testing.T) {
+	s := mustCreate(t, "s", "string", []interface{}{"1", "yes", nil, "300"})
+
+	_, _, err := s.AsType("bool", CastStrict)
+	if err == nil || !strings.Contains(err.Error(), `s at position 0: cannot parse "1" as bool`) {
+		t.Errorf("got error %v, want a parse error at position 0", err)
+	}
+
+	got, report, err := s.AsType("int8", CastLenient)
+	if err != nil {
+		t.Fatalf("AsType: %v", err)
+	}
+	checkSeries(t, got, "int8", []interface{}{int8(1), nil, nil, nil})
+	if len(report) != 2 || report[0].Position != 1 || report[1].Position != 3 || report[1].Value != "300" {
+		t.Errorf("got report %v, want positions 1 and 3", report)
+	}
+}
+
+func TestAsTypeCategory(t *testing.T) {
+	s := mustCategory(t, []interface{}{"2", nil, "1"})
+	ints, _, err := s.AsType("int", CastStrict)
+	if err != nil {
+		t.Fatalf("AsType: %v", err)
+	}
+	checkSeries(t, ints, "int", []interface{}{2, nil, 1})
+
+	back, _, err := ints.AsType("category", CastStrict)
+	if err != nil {
+		t.Fatalf("AsType: %v", err)
+	}
+	if got := labelValues(t, back); got[0] != "2" || got[1] != nil || got[2] != "1" {
+		t.Errorf("labels: got %v", got)
+	}
+}
+
+func TestParseBool(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    bool
+		wantErr bool
+	}{
+		{"true", true, false},
+		{"FALSE", false, false},
+		{"True", true, false},
+		{"1", false, true},
+		{"0", false, true},
+		{"yes", false, true},
+		{"t", false, true},
+		{"", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseBool(tt.input)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("got %v, %v, want %v, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAsTypeErrors(t *testing.T) {
+	s := mustCreate(t, "s", "string", []interface{}{"x"})
+	tests := []struct {
+		name     string
+		datatype string
+		mode     CastMode
+		want     string
+	}{
+		{"invalid type", "huge", CastStrict, "invalid type: huge"},
+		{"invalid mode", "int", CastMode(7), "invalid cast mode"},
+		{"not a list", "list<int>", CastStrict, `cannot parse "x" as list<int>`},
+		{"not a struct", "struct", CastStrict, `cannot parse "x" as struct`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := s.AsType(tt.datatype, tt.mode)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	number := mustCreate(t, "s", "string", []interface{}{"5"})
+	if _, _, err := number.AsType("struct", CastStrict); err == nil {
+		t.Error("expected an error casting a number to struct")
+	}
+}
@@ -0,0 +1,407 @@
+package series
+
+import (
+	"cmp"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Categories is the dictionary of a category Series: the ordered labels its
+// values may take. Each value of the Series is a Category holding the code of
+// its label, its position in the dictionary, so that values compare as
+// integers and every label is stored once. A Categories is never changed once
+// built, the methods of CategoryAccessor build new ones.
+type Categories struct {
+	labels []string
+	codes  map[string]int
+	values []interface{} // Boxed Category of each code, shared by every Entry
+}
+
+// Category is a value of a category Series
+type Category struct {
+	code       int
+	categories *Categories
+}
+
+// NewCategories builds a dictionary holding the labels in the given order
+func NewCategories(labels []string) (*Categories, error) {
+	c := &Categories{
+		labels: slices.Clone(labels),
+		codes:  make(map[string]int, len(labels)),
+		values: make([]interface{}, len(labels)),
+	}
+	for code, label := range c.labels {
+		if _, exists := c.codes[label]; exists {
+			return nil, fmt.Errorf("duplicate category: %s", label)
+		}
+		c.codes[label] = code
+		c.values[code] = Category{code: code, categories: c}
+	}
+	return c, nil
+}
+
+// Labels returns the labels of the dictionary in order
+func (c *Categories) Labels() []string {
+	return slices.Clone(c.labels)
+}
+
+// Len returns the number of labels in the dictionary
+func (c *Categories) Len() int {
+	return len(c.labels)
+}
+
+// Get returns the Category of a label, reporting false when the label is not
+// in the dictionary
+func (c *Categories) Get(label string) (Category, bool) {
+	code, ok := c.codes[label]
+	if !ok {
+		return Category{}, false
+	}
+	return c.values[code].(Category), true
+}
+
+// Value returns the Category of a code boxed once for every Entry holding it,
+// so that storing it does not allocate. It is nil when the code is out of
+// range.
+func (c *Categories) Value(code int) interface{} {
+	if code < 0 || code >= len(c.values) {
+		return nil
+	}
+	return c.values[code]
+}
+
+// labelValue returns the boxed Category of a label, or nil when the label is
+// not in the dictionary
+func (c *Categories) labelValue(label string) interface{} {
+	if code, ok := c.codes[label]; ok {
+		return c.values[code]
+	}
+	return nil
+}
+
+// Code returns the position of the label in its dictionary
+func (c Category) Code() int {
+	return c.code
+}
+
+// Label returns the label of the category
+func (c Category) Label() string {
+	if c.categories == nil {
+		return ""
+	}
+	return c.categories.labels[c.code]
+}
+
+// Categories returns the dictionary the category belongs to
+func (c Category) Categories() *Categories {
+	return c.categories
+}
+
+// String returns the label of the category
+func (c Category) String() string {
+	return c.Label()
+}
+
+// MarshalJSON writes the category as its label
+func (c Category) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Label())
+}
+
+// Value writes the category to SQL drivers as its label
+func (c Category) Value() (driver.Value, error) {
+	return c.Label(), nil
+}
+
+// compareCategory orders two values when at least one is a Category and the
+// other is a Category or a string. Categories of the same dictionary compare
+// by code, following the order of the dictionary, others by label. It reports
+// false for other values.
+func compareCategory(a interface{}, b interface{}) (int, bool) {
+	ca, aIsCategory := a.(Category)
+	cb, bIsCategory := b.(Category)
+	switch {
+	case aIsCategory && bIsCategory:
+		if ca.categories == cb.categories {
+			return cmp.Compare(ca.code, cb.code), true
+		}
+		return cmp.Compare(ca.Label(), cb.Label()), true
+	case aIsCategory:
+		if s, ok := b.(string); ok {
+			return cmp.Compare(ca.Label(), s), true
+		}
+	case bIsCategory:
+		if s, ok := a.(string); ok {
+			return cmp.Compare(s, cb.Label()), true
+		}
+	}
+	return 0, false
+}
+
+// AsCategory converts a string Series into a category Series. The dictionary
+// holds the given categories in order, and strings missing from them become
+// nil. Without categories it holds the distinct strings in sorted order. Nil
+// values stay nil and each Entry keeps its Index. A category Series is recoded
+// onto the given categories, or returned unchanged when there are none.
+func (s *Series) AsCategory(categories ...string) (*Series, error) {
+	switch s.Datatype {
+	case "category":
+		if len(categories) == 0 {
+			return s, nil
+		}
+		return s.Cat().recode(categories, nil)
+	case "string":
+	default:
+		return nil, fmt.Errorf("invalid type: expected string, got %s", s.Datatype)
+	}
+
+	labels := categories
+	if len(labels) == 0 {
+		seen := make(map[string]bool)
+		for i, entry := range s.Data {
+			if entry.Value == nil {
+				continue
+			}
+			label, ok := entry.Value.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s at position %d: invalid type: expected string, got %T", s.Name, i, entry.Value)
+			}
+			if !seen[label] {
+				seen[label] = true
+				labels = append(labels, label)
+			}
+		}
+		slices.Sort(labels)
+	}
+
+	dictionary, err := NewCategories(labels)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]Entry, len(s.Data))
+	for i, entry := range s.Data {
+		data[i].Index = entry.Index
+		if entry.Value == nil {
+			continue
+		}
+		label, ok := entry.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s at position %d: invalid type: expected string, got %T", s.Name, i, entry.Value)
+		}
+		data[i].Value = dictionary.labelValue(label)
+	}
+
+	return &Series{
+		Name:     s.Name,
+		Datatype: "category",
+		Data:     data,
+	}, nil
+}
+
+// CategoryAccessor reads and changes the dictionary of a category Series.
+// Every change returns a new Series with a new dictionary.
+type CategoryAccessor struct {
+	s *Series
+}
+
+// Cat returns the category accessor of the series
+func (s *Series) Cat() *CategoryAccessor {
+	return &CategoryAccessor{s: s}
+}
+
+// Categories returns the labels of the dictionary in order
+func (cat *CategoryAccessor) Categories() ([]string, error) {
+	categories, err := cat.Dictionary()
+	if err != nil {
+		return nil, err
+	}
+	return categories.Labels(), nil
+}
+
+// Codes returns the code of each value as an int Series, nil values stay nil
+func (cat *CategoryAccessor) Codes() (*Series, error) {
+	return cat.apply("int", func(c Category) interface{} { return c.code })
+}
+
+// AsString converts the series back into a string Series of labels
+func (cat *CategoryAccessor) AsString() (*Series, error) {
+	return cat.apply("string", func(c Category) interface{} { return c.Label() })
+}
+
+// AddCategories appends labels to the end of the dictionary
+func (cat *CategoryAccessor) AddCategories(labels ...string) (*Series, error) {
+	categories, err := cat.Dictionary()
+	if err != nil {
+		return nil, err
+	}
+	return cat.recode(append(categories.Labels(), labels...), nil)
+}
+
+// RemoveCategories removes labels from the dictionary, values holding one of
+// them become nil
+func (cat *CategoryAccessor) RemoveCategories(labels ...string) (*Series, error) {
+	categories, err := cat.Dictionary()
+	if err != nil {
+		return nil, err
+	}
+	for _, label := range labels {
+		if _, ok := categories.codes[label]; !ok {
+			return nil, fmt.Errorf("category does not exist: %s", label)
+		}
+	}
+	kept := slices.DeleteFunc(categories.Labels(), func(label string) bool {
+		return slices.Contains(labels, label)
+	})
+	return cat.recode(kept, nil)
+}
+
+// ReorderCategories changes the order of the dictionary, which must hold the
+// same labels
+func (cat *CategoryAccessor) ReorderCategories(labels []string) (*Series, error) {
+	categories, err := cat.Dictionary()
+	if err != nil {
+		return nil, err
+	}
+	sorted, current := slices.Sorted(slices.Values(labels)), categories.Labels()
+	slices.Sort(current)
+	if !slices.Equal(sorted, current) {
+		return nil, fmt.Errorf("categories must be reordered, not changed: %v", labels)
+	}
+	return cat.recode(labels, nil)
+}
+
+// RenameCategories renames the labels found in mapping, keeping their codes.
+// Labels missing from mapping keep their name.
+func (cat *CategoryAccessor) RenameCategories(mapping map[string]string) (*Series, error) {
+	categories, err := cat.Dictionary()
+	if err != nil {
+		return nil, err
+	}
+	labels := categories.Labels()
+	for i, label := range labels {
+		if renamed, ok := mapping[label]; ok {
+			labels[i] = renamed
+		}
+	}
+	return cat.recode(labels, mapping)
+}
+
+// Dictionary returns the dictionary of the series, found through its first
+// non-nil value. A series without values has an empty dictionary.
+func (cat *CategoryAccessor) Dictionary() (*Categories, error) {
+	s := cat.s
+	if s.Datatype != "category" {
+		return nil, fmt.Errorf("invalid type: expected category, got %s", s.Datatype)
+	}
+	for i, entry := range s.Data {
+		if entry.Value == nil {
+			continue
+		}
+		c, ok := entry.Value.(Category)
+		if !ok {
+			return nil, fmt.Errorf("%s at position %d: invalid type: expected category, got %T", s.Name, i, entry.Value)
+		}
+		return c.categories, nil
+	}
+	return NewCategories(nil)
+}
+
+// recode builds a new category Series over a dictionary of the given labels.
+// Each value keeps its label, renamed through mapping when it is not nil, and
+// becomes nil when the label is no longer in the dictionary.
+func (cat *CategoryAccessor) recode(labels []string, mapping map[string]string) (*Series, error) {
+	if _, err := cat.Dictionary(); err != nil {
+		return nil, err
+	}
+	categories, err := NewCategories(labels)
+	if err != nil {
+		return nil, err
+	}
+	return cat.recodeOnto(categories, mapping)
+}
+
+// recodeOnto builds a new category Series over the given dictionary, as
+// recode does
+func (cat *CategoryAccessor) recodeOnto(categories *Categories, mapping map[string]string) (*Series, error) {
+	return cat.apply("category", func(c Category) interface{} {
+		label := c.Label()
+		if renamed, ok := mapping[label]; ok {
+			label = renamed
+		}
+		return categories.labelValue(label)
+	})
+}
+
+// apply builds a new Series of the given datatype by calling fn on every
+// value. Nil values stay nil and each Entry keeps its Index.
+func (cat *CategoryAccessor) apply(datatype string, fn func(c Category) interface{}) (*Series, error) {
+	s := cat.s
+	if s.Datatype != "category" {
+		return nil, fmt.Errorf("invalid type: expected category, got %s", s.Datatype)
+	}
+
+	data := make([]Entry, len(s.Data))
+	for i, entry := range s.Data {
+		data[i].Index = entry.Index
+		if entry.Value == nil {
+			continue
+		}
+		c, ok := entry.Value.(Category)
+		if !ok {
+			return nil, fmt.Errorf("%s at position %d: invalid type: expected category, got %T", s.Name, i, entry.Value)
+		}
+		data[i].Value = fn(c)
+	}
+
+	return &Series{
+		Name:     s.Name,
+		Datatype: datatype,
+		Data:     data,
+	}, nil
+}
+
+// UnifyCategories recodes category Series onto one shared dictionary, so that
+// their values can be compared by code. The dictionary holds the labels of the
+// first Series followed by the labels only found in the later ones. Series
+// already sharing a dictionary are returned unchanged.
+func UnifyCategories(list ...*Series) ([]*Series, error) {
+	dictionaries := make([]*Categories, len(list))
+	shared := true
+	for i, s := range list {
+		categories, err := s.Cat().Dictionary()
+		if err != nil {
+			return nil, err
+		}
+		dictionaries[i] = categories
+		shared = shared && categories == dictionaries[0]
+	}
+	if shared {
+		return list, nil
+	}
+
+	labels := []string{}
+	seen := make(map[string]bool)
+	for _, categories := range dictionaries {
+		for _, label := range categories.labels {
+			if !seen[label] {
+				seen[label] = true
+				labels = append(labels, label)
+			}
+		}
+	}
+
+	categories, err := NewCategories(labels)
+	if err != nil {
+		return nil, err
+	}
+	unified := make([]*Series, len(list))
+	for i, s := range list {
+		recoded, err := s.Cat().recodeOnto(categories, nil)
+		if err != nil {
+			return nil, err
+		}
+		unified[i] = recoded
+	}
+	return unified, nil
+}
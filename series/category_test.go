@@ -0,0 +1,264 @@
+package series
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// labelValues returns the label of every value of a category series, nil
+// included
+func labelValues(t *testing.T, s *Series) []interface{} {
+	t.Helper()
+	labels, err := s.Cat().AsString()
+	if err != nil {
+		t.Fatalf("AsString: %v", err)
+	}
+	return entryValues(labels)
+}
+
+// mustCategory builds a category series from string values
+func mustCategory(t *testing.T, values []interface{}, categories ...string) *Series {
+	t.Helper()
+	s, err := mustCreate(t, "c", "string", values).AsCategory(categories...)
+	if err != nil {
+		t.Fatalf("AsCategory: %v", err)
+	}
+	return s
+}
+
+func TestAsCategory(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     []interface{}
+		categories []string
+		labels     []string
+		want       []interface{}
+		codes      []interface{}
+	}{
+		{
+			name:   "sorted distinct labels",
+			values: []interface{}{"b", nil, "a", "b"},
+			labels: []string{"a", "b"},
+			want:   []interface{}{"b", nil, "a", "b"},
+			codes:  []interface{}{1, nil, 0, 1},
+		},
+		{
+			name:       "given order",
+			values:     []interface{}{"low", "high", "mid"},
+			categories: []string{"low", "mid", "high"},
+			labels:     []string{"low", "mid", "high"},
+			want:       []interface{}{"low", "high", "mid"},
+			codes:      []interface{}{0, 2, 1},
+		},
+		{
+			name:       "missing labels become nil",
+			values:     []interface{}{"x", "y"},
+			categories: []string{"y"},
+			labels:     []string{"y"},
+			want:       []interface{}{nil, "y"},
+			codes:      []interface{}{nil, 0},
+		},
+		{
+			name:   "no values",
+			values: []interface{}{nil},
+			labels: []string{},
+			want:   []interface{}{nil},
+			codes:  []interface{}{nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := mustCategory(t, tt.values, tt.categories...)
+			if s.Datatype != "category" {
+				t.Fatalf("got datatype %s", s.Datatype)
+			}
+			labels, err := s.Cat().Categories()
+			if err != nil {
+				t.Fatalf("Categories: %v", err)
+			}
+			if !slices.Equal(labels, tt.labels) {
+				t.Errorf("categories: got %v, want %v", labels, tt.labels)
+			}
+			if got := labelValues(t, s); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("labels: got %v, want %v", got, tt.want)
+			}
+			codes, err := s.Cat().Codes()
+			if err != nil {
+				t.Fatalf("Codes: %v", err)
+			}
+			checkSeries(t, codes, "int", tt.codes)
+		})
+	}
+}
+
+func TestCategoryAccessor(t *testing.T) {
+	s := mustCategory(t, []interface{}{"a", "b", nil, "c"})
+	tests := []struct {
+		name   string
+		apply  func() (*Series, error)
+		labels []string
+		want   []interface{}
+	}{
+		{
+			name:   "add",
+			apply:  func() (*Series, error) { return s.Cat().AddCategories("d") },
+			labels: []string{"a", "b", "c", "d"},
+			want:   []interface{}{"a", "b", nil, "c"},
+		},
+		{
+			name:   "remove",
+			apply:  func() (*Series, error) { return s.Cat().RemoveCategories("b") },
+			labels: []string{"a", "c"},
+			want:   []interface{}{"a", nil, nil, "c"},
+		},
+		{
+			name:   "reorder",
+			apply:  func() (*Series, error) { return s.Cat().ReorderCategories([]string{"c", "a", "b"}) },
+			labels: []string{"c", "a", "b"},
+			want:   []interface{}{"a", "b", nil, "c"},
+		},
+		{
+			name:   "rename",
+			apply:  func() (*Series, error) { return s.Cat().RenameCategories(map[string]string{"a": "z"}) },
+			labels: []string{"z", "b", "c"},
+			want:   []interface{}{"z", "b", nil, "c"},
+		},
+		{
+			name:   "recode a category series",
+			apply:  func() (*Series, error) { return s.AsCategory("c", "a") },
+			labels: []string{"c", "a"},
+			want:   []interface{}{"a", nil, nil, "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.apply()
+			if err != nil {
+				t.Fatalf("got error %v", err)
+			}
+			labels, _ := got.Cat().Categories()
+			if !reflect.DeepEqual(labels, tt.labels) {
+				t.Errorf("categories: got %v, want %v", labels, tt.labels)
+			}
+			if values := labelValues(t, got); !reflect.DeepEqual(values, tt.want) {
+				t.Errorf("labels: got %v, want %v", values, tt.want)
+			}
+		})
+	}
+
+	// The original series keeps its dictionary
+	if labels, _ := s.Cat().Categories(); !reflect.DeepEqual(labels, []string{"a", "b", "c"}) {
+		t.Errorf("original categories changed to %v", labels)
+	}
+}
+
+func TestCategoryErrors(t *testing.T) {
+	s := mustCategory(t, []interface{}{"a", "b"})
+	tests := []struct {
+		name  string
+		apply func() error
+		want  string
+	}{
+		{"duplicate", func() error { _, err := NewCategories([]string{"a", "a"}); return err }, "duplicate category: a"},
+		{"add existing", func() error { _, err := s.Cat().AddCategories("a"); return err }, "duplicate category: a"},
+		{"remove missing", func() error { _, err := s.Cat().RemoveCategories("z"); return err }, "category does not exist: z"},
+		{"reorder changes labels", func() error { _, err := s.Cat().ReorderCategories([]string{"a", "z"}); return err }, "must be reordered"},
+		{"rename onto existing", func() error { _, err := s.Cat().RenameCategories(map[string]string{"a": "b"}); return err }, "duplicate category: b"},
+		{"not a category", func() error { _, err := mustCreate(t, "n", "int", []interface{}{1}).Cat().Codes(); return err }, "expected category, got int"},
+		{"not a string", func() error { _, err := mustCreate(t, "n", "int", []interface{}{1}).AsCategory(); return err }, "expected string, got int"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.apply()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCategoryCompare(t *testing.T) {
+	ordered := mustCategory(t, []interface{}{"low", "high"}, "low", "high")
+	other := mustCategory(t, []interface{}{"high"}, "high")
+	low, high := ordered.Data[0].Value, ordered.Data[1].Value
+
+	tests := []struct {
+		name string
+		a, b interface{}
+		want int
+	}{
+		{"same dictionary by code", low, high, -1},
+		{"other dictionaries by label", high, other.Data[0].Value, 0},
+		{"category and string", high, "apple", 1},
+		{"string and category", "apple", low, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compare(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("Compare: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if !Equal(high, other.Data[0].Value) || !Equal(low, "low") || Equal(low, "high") {
+		t.Error("categories should equal by label")
+	}
+}
+
+func TestUnifyCategories(t *testing.T) {
+	a := mustCategory(t, []interface{}{"x", "y"})
+	b := mustCategory(t, []interface{}{"z", "x"})
+	unified, err := UnifyCategories(a, b)
+	if err != nil {
+		t.Fatalf("UnifyCategories: %v", err)
+	}
+	for _, s := range unified {
+		if labels, _ := s.Cat().Categories(); !reflect.DeepEqual(labels, []string{"x", "y", "z"}) {
+			t.Errorf("categories: got %v", labels)
+		}
+	}
+	if got := labelValues(t, unified[1]); !reflect.DeepEqual(got, []interface{}{"z", "x"}) {
+		t.Errorf("labels: got %v", got)
+	}
+	if unified[0].Data[0].Value != unified[1].Data[1].Value {
+		t.Error("shared labels should hold the same value")
+	}
+
+	same, err := UnifyCategories(unified...)
+	if err != nil || same[0] != unified[0] || same[1] != unified[1] {
+		t.Errorf("series sharing a dictionary should be returned unchanged, got error %v", err)
+	}
+}
+
+func TestCategoryJSON(t *testing.T) {
+	s := mustCategory(t, []interface{}{"b", nil, "a"}, "b", "a", "unused")
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	want := `{"name":"c","datatype":"category","categories":["b","a","unused"],"data":["b",null,"a"]}`
+	if string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+
+	var back Series
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	labels, _ := back.Cat().Categories()
+	if back.Datatype != "category" || !reflect.DeepEqual(labels, []string{"b", "a", "unused"}) {
+		t.Errorf("got %s with categories %v", back.Datatype, labels)
+	}
+	if got := labelValues(t, &back); !reflect.DeepEqual(got, []interface{}{"b", nil, "a"}) {
+		t.Errorf("labels: got %v", got)
+	}
+}
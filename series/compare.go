@@ -0,0 +1,78 @@
+package series
+
+import "fmt"
+
+// Eq compares every value of the series with value as Equal does, giving a
+// bool Series. Comparisons follow SQL semantics, so a nil on either side
+// gives nil rather than true or false, and each Entry keeps its Index.
+func (s *Series) Eq(value interface{}) (*Series, error) {
+	return s.compareEach(value, func(a interface{}) (bool, error) {
+		return Equal(a, value), nil
+	})
+}
+
+// Ne is the negation of Eq, nil where either side is nil
+func (s *Series) Ne(value interface{}) (*Series, error) {
+	return s.compareEach(value, func(a interface{}) (bool, error) {
+		return !Equal(a, value), nil
+	})
+}
+
+// Lt reports where the values of the series order before value, using the
+// order of Compare. A nil on either side gives nil.
+func (s *Series) Lt(value interface{}) (*Series, error) {
+	return s.orderEach(value, func(order int) bool { return order < 0 })
+}
+
+// Le reports where the values of the series order before or equal to value.
+// A nil on either side gives nil.
+func (s *Series) Le(value interface{}) (*Series, error) {
+	return s.orderEach(value, func(order int) bool { return order <= 0 })
+}
+
+// Gt reports where the values of the series order after value. A nil on
+// either side gives nil.
+func (s *Series) Gt(value interface{}) (*Series, error) {
+	return s.orderEach(value, func(order int) bool { return order > 0 })
+}
+
+// Ge reports where the values of the series order after or equal to value. A
+// nil on either side gives nil.
+func (s *Series) Ge(value interface{}) (*Series, error) {
+	return s.orderEach(value, func(order int) bool { return order >= 0 })
+}
+
+// orderEach compares every value of the series with value using Compare and
+// keeps the ones whose order passes fn
+func (s *Series) orderEach(value interface{}, fn func(order int) bool) (*Series, error) {
+	return s.compareEach(value, func(a interface{}) (bool, error) {
+		order, err := Compare(a, value)
+		if err != nil {
+			return false, err
+		}
+		return fn(order), nil
+	})
+}
+
+// compareEach builds a bool Series by calling fn on every non-nil value. Every
+// value is nil when value is nil.
+func (s *Series) compareEach(value interface{}, fn func(a interface{}) (bool, error)) (*Series, error) {
+	data := make([]Entry, len(s.Data))
+	for i, entry := range s.Data {
+		data[i].Index = entry.Index
+		if entry.Value == nil || value == nil {
+			continue
+		}
+		result, err := fn(entry.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s at position %d: %v", s.Name, i, err)
+		}
+		data[i].Value = result
+	}
+
+	return &Series{
+		Name:     s.Name,
+		Datatype: "bool",
+		Data:     data,
+	}, nil
+}
@@ -0,0 +1,59 @@
+package series
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompareOperators(t *testing.T) {
+	ints := mustCreate(t, "n", "int", []interface{}{1, 2, nil, 3})
+	when := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	times := mustCreate(t, "t", "datetime", []interface{}{when, nil})
+
+	tests := []struct {
+		name string
+		op   func() (*Series, error)
+		want []interface{}
+	}{
+		{"eq", func() (*Series, error) { return ints.Eq(2) }, []interface{}{false, true, nil, false}},
+		{"eq across widths", func() (*Series, error) { return ints.Eq(int8(3)) }, []interface{}{false, false, nil, true}},
+		{"ne", func() (*Series, error) { return ints.Ne(2) }, []interface{}{true, false, nil, true}},
+		{"lt", func() (*Series, error) { return ints.Lt(2) }, []interface{}{true, false, nil, false}},
+		{"le", func() (*Series, error) { return ints.Le(2) }, []interface{}{true, true, nil, false}},
+		{"gt", func() (*Series, error) { return ints.Gt(2.5) }, []interface{}{false, false, nil, true}},
+		{"ge", func() (*Series, error) { return ints.Ge(2) }, []interface{}{false, true, nil, true}},
+		{"eq nil", func() (*Series, error) { return ints.Eq(nil) }, []interface{}{nil, nil, nil, nil}},
+		{"ne nil", func() (*Series, error) { return ints.Ne(nil) }, []interface{}{nil, nil, nil, nil}},
+		{"datetime in another zone", func() (*Series, error) { return times.Eq(when.In(time.FixedZone("X", 3600))) }, []interface{}{true, nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.op()
+			if err != nil {
+				t.Fatalf("got error %v", err)
+			}
+			checkSeries(t, got, "bool", tt.want)
+		})
+	}
+}
+
+func TestCompareOperatorsKeepIndex(t *testing.T) {
+	s := mustCreate(t, "n", "int", []interface{}{1, nil})
+	s.Data[0].Index, s.Data[1].Index = 5, 9
+	got, err := s.Gt(0)
+	if err != nil {
+		t.Fatalf("Gt: %v", err)
+	}
+	if got.Data[0].Index != 5 || got.Data[1].Index != 9 {
+		t.Errorf("got indexes %d and %d, want 5 and 9", got.Data[0].Index, got.Data[1].Index)
+	}
+}
+
+func TestCompareOperatorsErrors(t *testing.T) {
+	s := mustCreate(t, "n", "int", []interface{}{nil, 1})
+	_, err := s.Lt("x")
+	if err == nil || !strings.Contains(err.Error(), "n at position 1") {
+		t.Errorf("got error %v, want an error at position 1", err)
+	}
+}
@@ -0,0 +1,86 @@
+package series
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultDatetimeLayouts are the layouts tried when parsing a datetime and no
+// layouts are given, most specific first
+var DefaultDatetimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// DisplayDatetimeLayout is the layout used to show datetime values as text
+const DisplayDatetimeLayout = "2006-01-02 15:04:05.999999999Z07:00"
+
+// ParseDatetime parses s with the first of the layouts that accepts it. Empty
+// layouts fall back to DefaultDatetimeLayouts. Values without a zone are UTC.
+func ParseDatetime(s string, layouts []string) (time.Time, error) {
+	if len(layouts) == 0 {
+		layouts = DefaultDatetimeLayouts
+	}
+	s = strings.TrimSpace(s)
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as datetime", s)
+}
+
+// FormatValue returns the text used to show a value, formatting datetimes with
+// DisplayDatetimeLayout, the elements of lists one by one and structs as JSON
+func FormatValue(value interface{}) string {
+	if t, ok := value.(time.Time); ok {
+		return t.Format(DisplayDatetimeLayout)
+	}
+	if isStruct(value) {
+		return formatJSON(value)
+	}
+	if isList(value) {
+		elems, _ := ListElements(value)
+		texts := make([]string, len(elems))
+		for i, elem := range elems {
+			texts[i] = FormatValue(elem)
+		}
+		return "[" + strings.Join(texts, ", ") + "]"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// Equal reports whether two values are equal. Datetimes are equal when they
+// are the same instant, whatever their location, and numbers of different
+// widths, decimals included, are equal when they hold the same value.
+// Categories of one dictionary are equal when their codes are, and otherwise
+// when their labels are, which lets a category equal a string. Lists are
+// equal when their elements are, and structs when their fields are. Nil only
+// equals nil, Eq and joins treat it as unknown instead.
+func Equal(a interface{}, b interface{}) bool {
+	if isStruct(a) || isStruct(b) {
+		return equalStructs(a, b)
+	}
+	if isList(a) || isList(b) {
+		return equalLists(a, b)
+	}
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		return ok && ta.Equal(tb)
+	}
+	if order, ok := compareDecimal(a, b); ok {
+		return order == 0
+	}
+	if order, ok := compareCategory(a, b); ok {
+		return order == 0
+	}
+	if na, ok := toNumber(a); ok {
+		nb, ok := toNumber(b)
+		return ok && compareNumbers(na, nb) == 0
+	}
+	return a == b
+}
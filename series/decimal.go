@@ -0,0 +1,487 @@
+package series
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// MaxDecimalPrecision is the largest number of digits a Decimal can hold
+const MaxDecimalPrecision = 38
+
+// Decimal is an exact decimal number: an integer coefficient of at most
+// MaxDecimalPrecision digits scaled down by a power of ten. The coefficient is
+// stored as a 128-bit two's complement integer, so a Decimal is comparable and
+// can be used as a map key, for instance when grouping or joining. Decimals
+// with the same value but different scales are not ==, which is never an issue
+// inside a decimal Series as all its values share the scale of the datatype.
+type Decimal struct {
+	hi    int64
+	lo    uint64
+	scale int32
+}
+
+// RoundingMode says how a Decimal is rounded when an operation gives more
+// digits than the result scale can hold
+type RoundingMode int
+
+const (
+	RoundHalfEven RoundingMode = iota // Round to nearest, ties to the even digit
+	RoundHalfUp                       // Round to nearest, ties away from zero
+	RoundHalfDown                     // Round to nearest, ties towards zero
+	RoundDown                         // Round towards zero
+	RoundUp                           // Round away from zero
+	RoundFloor                        // Round towards negative infinity
+	RoundCeiling                      // Round towards positive infinity
+	RoundExact                        // Fail instead of rounding
+)
+
+var (
+	bigTen      = big.NewInt(10)
+	maxDecimal  = new(big.Int).Exp(bigTen, big.NewInt(MaxDecimalPrecision), nil)
+	lowWordMask = new(big.Int).SetUint64(^uint64(0))
+)
+
+// pow10 returns 10 to the power n
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// NewDecimal returns unscaled divided by 10 to the power scale, so that
+// NewDecimal(1234, 2) is 12.34
+func NewDecimal(unscaled int64, scale int) (Decimal, error) {
+	return NewDecimalFromBig(big.NewInt(unscaled), scale)
+}
+
+// NewDecimalFromBig returns coefficient divided by 10 to the power scale. The
+// coefficient must have at most MaxDecimalPrecision digits.
+func NewDecimalFromBig(coefficient *big.Int, scale int) (Decimal, error) {
+	if scale < 0 || scale > MaxDecimalPrecision {
+		return Decimal{}, fmt.Errorf("invalid decimal scale: %d", scale)
+	}
+	if new(big.Int).Abs(coefficient).Cmp(maxDecimal) >= 0 {
+		return Decimal{}, fmt.Errorf("decimal overflows %d digits", MaxDecimalPrecision)
+	}
+	lo := new(big.Int).And(coefficient, lowWordMask).Uint64()
+	hi := new(big.Int).Rsh(coefficient, 64).Int64()
+	return Decimal{hi: hi, lo: lo, scale: int32(scale)}, nil
+}
+
+// ParseDecimal parses a decimal number such as "-12.50" or "1.5e3". The scale
+// of the result is the number of digits after the point, less the exponent.
+func ParseDecimal(s string) (Decimal, error) {
+	text := strings.TrimSpace(s)
+	mantissa, exponent := text, 0
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		var err error
+		mantissa = text[:i]
+		if exponent, err = strconv.Atoi(text[i+1:]); err != nil {
+			return Decimal{}, fmt.Errorf("cannot parse %q as decimal", s)
+		}
+	}
+
+	sign := ""
+	if mantissa != "" && (mantissa[0] == '-' || mantissa[0] == '+') {
+		sign, mantissa = mantissa[:1], mantissa[1:]
+	}
+	whole, fraction, _ := strings.Cut(mantissa, ".")
+	digits := whole + fraction
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("cannot parse %q as decimal", s)
+	}
+
+	// Any exponent beyond this overflows or underflows every Decimal, and
+	// would otherwise build an enormous power of ten
+	if limit := MaxDecimalPrecision + len(digits); exponent > limit || exponent < -limit {
+		return Decimal{}, fmt.Errorf("cannot parse %q as decimal: exponent out of range", s)
+	}
+
+	coefficient, _ := new(big.Int).SetString(sign+digits, 10)
+	scale := len(fraction) - exponent
+	if scale < 0 {
+		coefficient.Mul(coefficient, pow10(-scale))
+		scale = 0
+	}
+	d, err := NewDecimalFromBig(coefficient, scale)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("cannot parse %q as decimal: %v", s, err)
+	}
+	return d, nil
+}
+
+// Coefficient returns the unscaled integer value of the decimal
+func (d Decimal) Coefficient() *big.Int {
+	c := big.NewInt(d.hi)
+	c.Lsh(c, 64)
+	return c.Add(c, new(big.Int).SetUint64(d.lo))
+}
+
+// Scale returns the number of digits after the decimal point
+func (d Decimal) Scale() int {
+	return int(d.scale)
+}
+
+// Precision returns the number of digits in the coefficient
+func (d Decimal) Precision() int {
+	c := d.Coefficient()
+	if c.Sign() == 0 {
+		return 1
+	}
+	return len(c.Abs(c).String())
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of the decimal
+func (d Decimal) Sign() int {
+	return d.Coefficient().Sign()
+}
+
+// IsZero reports whether the decimal is zero
+func (d Decimal) IsZero() bool {
+	return d.hi == 0 && d.lo == 0
+}
+
+// Cmp compares two decimals by value, whatever their scales, returning -1, 0
+// or 1
+func (d Decimal) Cmp(other Decimal) int {
+	a, b := d.Coefficient(), other.Coefficient()
+	switch {
+	case d.scale < other.scale:
+		a.Mul(a, pow10(int(other.scale-d.scale)))
+	case d.scale > other.scale:
+		b.Mul(b, pow10(int(d.scale-other.scale)))
+	}
+	return a.Cmp(b)
+}
+
+// Neg returns the decimal with its sign flipped
+func (d Decimal) Neg() Decimal {
+	c := d.Coefficient()
+	result, _ := NewDecimalFromBig(c.Neg(c), int(d.scale))
+	return result
+}
+
+// Add returns d + other exactly, at the larger of the two scales
+func (d Decimal) Add(other Decimal) (Decimal, error) {
+	a, b, scale := align(d, other)
+	return NewDecimalFromBig(a.Add(a, b), scale)
+}
+
+// Sub returns d - other exactly, at the larger of the two scales
+func (d Decimal) Sub(other Decimal) (Decimal, error) {
+	a, b, scale := align(d, other)
+	return NewDecimalFromBig(a.Sub(a, b), scale)
+}
+
+// Mul returns d * other rounded to the given scale
+func (d Decimal) Mul(other Decimal, scale int, mode RoundingMode) (Decimal, error) {
+	product := d.Coefficient()
+	product.Mul(product, other.Coefficient())
+	return rescale(product, int(d.scale+other.scale), scale, mode)
+}
+
+// Div returns d / other rounded to the given scale
+func (d Decimal) Div(other Decimal, scale int, mode RoundingMode) (Decimal, error) {
+	if other.IsZero() {
+		return Decimal{}, fmt.Errorf("decimal division by zero")
+	}
+	if scale < 0 || scale > MaxDecimalPrecision {
+		return Decimal{}, fmt.Errorf("invalid decimal scale: %d", scale)
+	}
+	num, den := d.Coefficient(), other.Coefficient()
+	if shift := scale - int(d.scale) + int(other.scale); shift >= 0 {
+		num.Mul(num, pow10(shift))
+	} else {
+		den.Mul(den, pow10(-shift))
+	}
+	quo, err := roundQuo(num, den, mode)
+	if err != nil {
+		return Decimal{}, err
+	}
+	return NewDecimalFromBig(quo, scale)
+}
+
+// Rescale returns the decimal with the given scale, rounding when digits are
+// dropped
+func (d Decimal) Rescale(scale int, mode RoundingMode) (Decimal, error) {
+	return rescale(d.Coefficient(), int(d.scale), scale, mode)
+}
+
+// Float64 returns the nearest float64 to the decimal
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String formats the decimal with exactly Scale digits after the point
+func (d Decimal) String() string {
+	c := d.Coefficient()
+	sign := ""
+	if c.Sign() < 0 {
+		sign = "-"
+		c.Neg(c)
+	}
+	digits := c.String()
+	if d.scale == 0 {
+		return sign + digits
+	}
+	scale := int(d.scale)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
+
+// MarshalJSON writes the decimal as a JSON number with all its digits
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Value writes the decimal to SQL drivers as text, so that no digits are lost
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// align returns the coefficients of two decimals at their larger scale
+func align(a Decimal, b Decimal) (*big.Int, *big.Int, int) {
+	ac, bc := a.Coefficient(), b.Coefficient()
+	switch {
+	case a.scale < b.scale:
+		ac.Mul(ac, pow10(int(b.scale-a.scale)))
+		return ac, bc, int(b.scale)
+	case a.scale > b.scale:
+		bc.Mul(bc, pow10(int(a.scale-b.scale)))
+	}
+	return ac, bc, int(a.scale)
+}
+
+// rescale turns a coefficient at one scale into a Decimal at another
+func rescale(coefficient *big.Int, from int, to int, mode RoundingMode) (Decimal, error) {
+	if to < 0 || to > MaxDecimalPrecision {
+		return Decimal{}, fmt.Errorf("invalid decimal scale: %d", to)
+	}
+	if to >= from {
+		return NewDecimalFromBig(coefficient.Mul(coefficient, pow10(to-from)), to)
+	}
+	quo, err := roundQuo(coefficient, pow10(from-to), mode)
+	if err != nil {
+		return Decimal{}, err
+	}
+	return NewDecimalFromBig(quo, to)
+}
+
+// roundQuo divides num by den, rounding the quotient with mode
+func roundQuo(num *big.Int, den *big.Int, mode RoundingMode) (*big.Int, error) {
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 {
+		return quo, nil
+	}
+
+	// Compare twice the remainder with the divisor to find ties
+	sign := num.Sign() * den.Sign()
+	twice := new(big.Int).Abs(rem)
+	half := twice.Lsh(twice, 1).Cmp(new(big.Int).Abs(den))
+
+	var away bool
+	switch mode {
+	case RoundHalfEven:
+		away = half > 0 || (half == 0 && quo.Bit(0) == 1)
+	case RoundHalfUp:
+		away = half >= 0
+	case RoundHalfDown:
+		away = half > 0
+	case RoundDown:
+		away = false
+	case RoundUp:
+		away = true
+	case RoundFloor:
+		away = sign < 0
+	case RoundCeiling:
+		away = sign > 0
+	case RoundExact:
+		return nil, fmt.Errorf("decimal cannot be stored exactly without rounding")
+	default:
+		return nil, fmt.Errorf("invalid rounding mode: %d", mode)
+	}
+	if away {
+		quo.Add(quo, big.NewInt(int64(sign)))
+	}
+	return quo, nil
+}
+
+// DecimalType returns the datatype of decimals with the given precision and
+// scale, such as "decimal(10,2)"
+func DecimalType(precision int, scale int) string {
+	return fmt.Sprintf("decimal(%d,%d)", precision, scale)
+}
+
+// ParseDecimalType returns the precision and scale of a decimal datatype. As
+// in SQL, "decimal" alone is decimal(38,0) and "decimal(p)" is decimal(p,0).
+func ParseDecimalType(datatype string) (int, int, bool) {
+	if datatype == "decimal" {
+		return MaxDecimalPrecision, 0, true
+	}
+	args, ok := strings.CutPrefix(datatype, "decimal(")
+	if !ok {
+		return 0, 0, false
+	}
+	args, ok = strings.CutSuffix(args, ")")
+	if !ok {
+		return 0, 0, false
+	}
+
+	p, s, hasScale := strings.Cut(args, ",")
+	precision, err := strconv.Atoi(strings.TrimSpace(p))
+	if err != nil {
+		return 0, 0, false
+	}
+	scale := 0
+	if hasScale {
+		if scale, err = strconv.Atoi(strings.TrimSpace(s)); err != nil {
+			return 0, 0, false
+		}
+	}
+	if precision < 1 || precision > MaxDecimalPrecision || scale < 0 || scale > precision {
+		return 0, 0, false
+	}
+	return precision, scale, true
+}
+
+// IsDecimalDatatype reports whether the datatype is a valid decimal datatype
+func IsDecimalDatatype(datatype string) bool {
+	_, _, ok := ParseDecimalType(datatype)
+	return ok
+}
+
+// isValidDecimal reports whether value is a Decimal that fits the decimal
+// datatype with the given precision and scale
+func isValidDecimal(value interface{}, precision int, scale int) bool {
+	d, ok := value.(Decimal)
+	return ok && d.Scale() == scale && d.Precision() <= precision
+}
+
+// toDecimal converts an integer, float or Decimal to a Decimal that fits the
+// decimal datatype without losing any digits
+func toDecimal(value interface{}, datatype string) (Decimal, error) {
+	precision, scale, _ := ParseDecimalType(datatype)
+
+	var d Decimal
+	var err error
+	switch v := value.(type) {
+	case Decimal:
+		d = v
+	case float32:
+		d, err = ParseDecimal(strconv.FormatFloat(float64(v), 'f', -1, 32))
+	case float64:
+		d, err = ParseDecimal(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		n, ok := toNumber(value)
+		if !ok {
+			return Decimal{}, fmt.Errorf("invalid type: expected %s, got %T", datatype, value)
+		}
+		if n.kind == 'u' {
+			d, err = NewDecimalFromBig(new(big.Int).SetUint64(n.u), 0)
+		} else {
+			d, err = NewDecimal(n.i, 0)
+		}
+	}
+	if err != nil {
+		return Decimal{}, fmt.Errorf("value %v cannot be stored as %s: %v", value, datatype, err)
+	}
+
+	if d, err = d.Rescale(scale, RoundExact); err != nil {
+		return Decimal{}, fmt.Errorf("value %v cannot be stored exactly as %s", value, datatype)
+	}
+	if d.Precision() > precision {
+		return Decimal{}, fmt.Errorf("value %v overflows %s", value, datatype)
+	}
+	return d, nil
+}
+
+// decimalDigits is the number of decimal digits needed for any value of each
+// integer datatype
+var decimalDigits = map[string]int{
+	"int8":   3,
+	"int16":  5,
+	"int32":  10,
+	"int64":  19,
+	"int":    19,
+	"uint8":  3,
+	"uint16": 5,
+	"uint32": 10,
+	"uint64": 20,
+}
+
+// promoteDecimal returns the decimal datatype that holds values of a decimal
+// datatype and another numeric datatype. Two decimals keep the larger scale
+// and the larger number of integer digits, and an integer needs as many
+// integer digits as its widest value. Floats give float, as a float cannot be
+// held exactly by a decimal.
+func promoteDecimal(a string, b string) (string, bool) {
+	if !IsDecimalDatatype(a) {
+		a, b = b, a
+	}
+	precision, scale, _ := ParseDecimalType(a)
+	intDigits := precision - scale
+
+	switch {
+	case IsDecimalDatatype(b):
+		p, s, _ := ParseDecimalType(b)
+		scale = max(scale, s)
+		intDigits = max(intDigits, p-s)
+	case decimalDigits[b] > 0:
+		intDigits = max(intDigits, decimalDigits[b])
+	case b == "float" || b == "float32":
+		return "float", true
+	default:
+		return "", false
+	}
+	return DecimalType(min(MaxDecimalPrecision, intDigits+scale), scale), true
+}
+
+// decimalNumber widens a Decimal to a number, keeping whole values that fit
+// 64 bits as integers so that they convert exactly
+func decimalNumber(d Decimal) number {
+	quo, rem := new(big.Int).QuoRem(d.Coefficient(), pow10(d.Scale()), new(big.Int))
+	switch {
+	case rem.Sign() != 0:
+	case quo.IsInt64():
+		return number{kind: 'i', i: quo.Int64()}
+	case quo.IsUint64():
+		return number{kind: 'u', u: quo.Uint64()}
+	}
+	return number{kind: 'f', f: d.Float64()}
+}
+
+// compareDecimal orders two values when at least one is a Decimal and the
+// other is a Decimal or a number. Integers are compared exactly, floats by the
+// nearest float64 to the Decimal. It reports false for other values.
+func compareDecimal(a interface{}, b interface{}) (int, bool) {
+	da, aIsDecimal := a.(Decimal)
+	db, bIsDecimal := b.(Decimal)
+	switch {
+	case aIsDecimal && bIsDecimal:
+		return da.Cmp(db), true
+	case aIsDecimal:
+		order, ok := compareDecimal(b, a)
+		return -order, ok
+	case !bIsDecimal:
+		return 0, false
+	}
+
+	// a is a number and b a Decimal
+	n, ok := toNumber(a)
+	if !ok {
+		return 0, false
+	}
+	switch n.kind {
+	case 'i':
+		da, _ = NewDecimal(n.i, 0)
+	case 'u':
+		da, _ = NewDecimalFromBig(new(big.Int).SetUint64(n.u), 0)
+	default:
+		return compareNumbers(n, number{kind: 'f', f: db.Float64()}), true
+	}
+	return da.Cmp(db), true
+}
@@ -0,0 +1,283 @@
+package series
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		scale int
+	}{
+		{"12.50", "12.50", 2},
+		{"-0.5", "-0.5", 1},
+		{"+7", "7", 0},
+		{"1.5e3", "1500", 0},
+		{"1.5E-3", "0.0015", 4},
+		{" 42 ", "42", 0},
+		{"0e-38", "0.00000000000000000000000000000000000000", 38},
+		{"1e37", "10000000000000000000000000000000000000", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			d, err := ParseDecimal(tt.input)
+			if err != nil {
+				t.Fatalf("ParseDecimal: %v", err)
+			}
+			if got := d.String(); got != tt.want || d.Scale() != tt.scale {
+				t.Errorf("got %s at scale %d, want %s at scale %d", got, d.Scale(), tt.want, tt.scale)
+			}
+		})
+	}
+}
+
+func TestParseDecimalErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", "cannot parse"},
+		{"1.2.3", "cannot parse"},
+		{"abc", "cannot parse"},
+		{"1e", "cannot parse"},
+		{"1e99999999", "exponent out of range"},
+		{"1e-99999999", "exponent out of range"},
+		{"1e99999999999999999999", "cannot parse"},
+		{"1e38", "overflows 38 digits"},
+		{"1e-39", "invalid decimal scale"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParseDecimal(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// mustParse parses a decimal or fails the test
+func mustParse(t *testing.T, s string) Decimal {
+	t.Helper()
+	d, err := ParseDecimal(s)
+	if err != nil {
+		t.Fatalf("ParseDecimal(%q): %v", s, err)
+	}
+	return d
+}
+
+func TestDecimalRescaleModes(t *testing.T) {
+	modes := []RoundingMode{RoundHalfEven, RoundHalfUp, RoundHalfDown, RoundDown, RoundUp, RoundFloor, RoundCeiling}
+	tests := []struct {
+		input string
+		scale int
+		want  [7]string // in the order of modes
+	}{
+		{"2.5", 0, [7]string{"2", "3", "2", "2", "3", "2", "3"}},
+		{"-2.5", 0, [7]string{"-2", "-3", "-2", "-2", "-3", "-3", "-2"}},
+		{"1.5", 0, [7]string{"2", "2", "1", "1", "2", "1", "2"}},
+		{"-1.5", 0, [7]string{"-2", "-2", "-1", "-1", "-2", "-2", "-1"}},
+		{"0.5", 0, [7]string{"0", "1", "0", "0", "1", "0", "1"}},
+		{"-0.5", 0, [7]string{"0", "-1", "0", "0", "-1", "-1", "0"}},
+		{"2.4", 0, [7]string{"2", "2", "2", "2", "3", "2", "3"}},
+		{"-2.6", 0, [7]string{"-3", "-3", "-3", "-2", "-3", "-3", "-2"}},
+		{"2.51", 0, [7]string{"3", "3", "3", "2", "3", "2", "3"}},
+		{"-2.51", 0, [7]string{"-3", "-3", "-3", "-2", "-3", "-3", "-2"}},
+		{"0.25", 1, [7]string{"0.2", "0.3", "0.2", "0.2", "0.3", "0.2", "0.3"}},
+		{"-0.35", 1, [7]string{"-0.4", "-0.4", "-0.3", "-0.3", "-0.4", "-0.4", "-0.3"}},
+		{"7.00", 0, [7]string{"7", "7", "7", "7", "7", "7", "7"}},
+		{"7", 2, [7]string{"7.00", "7.00", "7.00", "7.00", "7.00", "7.00", "7.00"}},
+	}
+	for _, tt := range tests {
+		for i, mode := range modes {
+			t.Run(fmt.Sprintf("%s to %d mode %d", tt.input, tt.scale, mode), func(t *testing.T) {
+				got, err := mustParse(t, tt.input).Rescale(tt.scale, mode)
+				if err != nil {
+					t.Fatalf("Rescale: %v", err)
+				}
+				if got.String() != tt.want[i] || got.Scale() != tt.scale {
+					t.Errorf("got %s at scale %d, want %s", got, got.Scale(), tt.want[i])
+				}
+			})
+		}
+	}
+
+	// Exact rescaling only fails when digits would be dropped
+	if got, err := mustParse(t, "7.00").Rescale(0, RoundExact); err != nil || got.String() != "7" {
+		t.Errorf("exact 7.00: got %s, %v", got, err)
+	}
+	if _, err := mustParse(t, "2.5").Rescale(0, RoundExact); err == nil || !strings.Contains(err.Error(), "without rounding") {
+		t.Errorf("exact 2.5: got error %v", err)
+	}
+	if _, err := mustParse(t, "2.5").Rescale(0, RoundingMode(99)); err == nil || !strings.Contains(err.Error(), "invalid rounding mode") {
+		t.Errorf("unknown mode: got error %v", err)
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	tests := []struct {
+		name  string
+		op    func(a, b Decimal) (Decimal, error)
+		a, b  string
+		want  string
+		scale int
+	}{
+		{"add aligns scales", Decimal.Add, "1.5", "2.25", "3.75", 2},
+		{"add negative", Decimal.Add, "-1.50", "0.5", "-1.00", 2},
+		{"sub", Decimal.Sub, "1", "0.001", "0.999", 3},
+		{"sub below zero", Decimal.Sub, "0.1", "0.25", "-0.15", 2},
+		{"mul exact", func(a, b Decimal) (Decimal, error) { return a.Mul(b, 3, RoundHalfEven) }, "1.5", "2.25", "3.375", 3},
+		{"mul rounds", func(a, b Decimal) (Decimal, error) { return a.Mul(b, 2, RoundHalfEven) }, "1.5", "2.25", "3.38", 2},
+		{"mul pads", func(a, b Decimal) (Decimal, error) { return a.Mul(b, 5, RoundHalfEven) }, "1.5", "-2.25", "-3.37500", 5},
+		{"mul truncates", func(a, b Decimal) (Decimal, error) { return a.Mul(b, 0, RoundDown) }, "-1.5", "2.25", "-3", 0},
+		{"div thirds", func(a, b Decimal) (Decimal, error) { return a.Div(b, 4, RoundHalfEven) }, "1", "3", "0.3333", 4},
+		{"div rounds up", func(a, b Decimal) (Decimal, error) { return a.Div(b, 2, RoundHalfEven) }, "2", "3", "0.67", 2},
+		{"div negative tie", func(a, b Decimal) (Decimal, error) { return a.Div(b, 2, RoundHalfEven) }, "-1", "8", "-0.12", 2},
+		{"div negative tie up", func(a, b Decimal) (Decimal, error) { return a.Div(b, 2, RoundHalfUp) }, "-1", "8", "-0.13", 2},
+		{"div smaller scale", func(a, b Decimal) (Decimal, error) { return a.Div(b, 1, RoundHalfEven) }, "10.00", "0.4", "25.0", 1},
+		{"div floor", func(a, b Decimal) (Decimal, error) { return a.Div(b, 0, RoundFloor) }, "7", "-2", "-4", 0},
+		{"div ceiling", func(a, b Decimal) (Decimal, error) { return a.Div(b, 0, RoundCeiling) }, "7", "-2", "-3", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.op(mustParse(t, tt.a), mustParse(t, tt.b))
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			if got.String() != tt.want || got.Scale() != tt.scale {
+				t.Errorf("got %s at scale %d, want %s at scale %d", got, got.Scale(), tt.want, tt.scale)
+			}
+		})
+	}
+}
+
+func TestDecimalArithmeticErrors(t *testing.T) {
+	nines := strings.Repeat("9", MaxDecimalPrecision)
+	tests := []struct {
+		name string
+		op   func() (Decimal, error)
+		want string
+	}{
+		{"add overflows", func() (Decimal, error) { return mustParse(t, nines).Add(mustParse(t, "1")) }, "overflows 38 digits"},
+		{"sub overflows", func() (Decimal, error) { return mustParse(t, "-"+nines).Sub(mustParse(t, "1")) }, "overflows 38 digits"},
+		{"add overflows when aligning", func() (Decimal, error) {
+			return mustParse(t, nines[1:]).Add(mustParse(t, "0.01"))
+		}, "overflows 38 digits"},
+		{"mul overflows", func() (Decimal, error) {
+			big := mustParse(t, nines[:20])
+			return big.Mul(big, 0, RoundHalfEven)
+		}, "overflows 38 digits"},
+		{"mul scale too large", func() (Decimal, error) { return mustParse(t, "1").Mul(mustParse(t, "1"), 39, RoundHalfEven) }, "invalid decimal scale"},
+		{"rescale overflows", func() (Decimal, error) { return mustParse(t, "10").Rescale(37, RoundHalfEven) }, "overflows 38 digits"},
+		{"div by zero", func() (Decimal, error) { return mustParse(t, "1").Div(mustParse(t, "0.00"), 2, RoundHalfEven) }, "division by zero"},
+		{"div scale too large", func() (Decimal, error) { return mustParse(t, "1").Div(mustParse(t, "3"), 39, RoundHalfEven) }, "invalid decimal scale"},
+		{"div overflows", func() (Decimal, error) { return mustParse(t, nines).Div(mustParse(t, "0.1"), 0, RoundHalfEven) }, "overflows 38 digits"},
+		{"div exact", func() (Decimal, error) { return mustParse(t, "1").Div(mustParse(t, "3"), 10, RoundExact) }, "without rounding"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.op()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromoteDecimal(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+		ok   bool
+	}{
+		{"decimal(5,2)", "decimal(7,4)", "decimal(7,4)", true},
+		{"decimal(10,2)", "decimal(5,4)", "decimal(12,4)", true},
+		{"decimal(38,30)", "decimal(38,2)", "decimal(38,30)", true},
+		{"decimal", "decimal(3,1)", "decimal(38,1)", true},
+		{"decimal(5,2)", "int8", "decimal(5,2)", true},
+		{"decimal(5,2)", "int32", "decimal(12,2)", true},
+		{"int", "decimal(4,1)", "decimal(20,1)", true},
+		{"decimal(30,10)", "int64", "decimal(30,10)", true},
+		{"decimal(38,30)", "int64", "decimal(38,30)", true},
+		{"uint64", "decimal(5,2)", "decimal(22,2)", true},
+		{"decimal(5,2)", "float32", "float", true},
+		{"float", "decimal(5,2)", "float", true},
+		{"decimal(5,2)", "string", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			got, ok := PromoteType(tt.a, tt.b)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("got %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestConvertToDecimal(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		datatype string
+		want     string
+		err      string
+	}{
+		{5, "decimal(5,2)", "5.00", ""},
+		{int8(-3), "decimal(3,0)", "-3", ""},
+		{uint64(math.MaxUint64), "decimal(20,0)", "18446744073709551615", ""},
+		{1.25, "decimal(5,2)", "1.25", ""},
+		{float32(0.5), "decimal(2,1)", "0.5", ""},
+		{mustParse(t, "1.50"), "decimal(4,1)", "1.5", ""},
+		{mustParse(t, "1.5"), "decimal(4,3)", "1.500", ""},
+		{1.255, "decimal(5,2)", "", "cannot be stored exactly"},
+		{mustParse(t, "1.55"), "decimal(4,1)", "", "cannot be stored exactly"},
+		{100000, "decimal(5,2)", "", "overflows decimal(5,2)"},
+		{uint64(math.MaxUint64), "decimal(19,0)", "", "overflows decimal(19,0)"},
+		{math.Inf(1), "decimal(5,2)", "", "cannot be stored as decimal(5,2)"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v as %s", tt.value, tt.datatype), func(t *testing.T) {
+			got, err := ConvertValue(tt.value, tt.datatype)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("got %v, error %v, want %q", got, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ConvertValue: %v", err)
+			}
+			if d, ok := got.(Decimal); !ok || d.String() != tt.want {
+				t.Errorf("got %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimalAggregates(t *testing.T) {
+	s := mustCreate(t, "d", "decimal(6,2)", []interface{}{
+		mustParse(t, "1.25"), nil, mustParse(t, "-0.50"), mustParse(t, "2.00"),
+	})
+	sum, err := s.Sum()
+	if err != nil || sum.(Decimal).String() != "2.75" {
+		t.Errorf("Sum: got %v, %v", sum, err)
+	}
+	// 2.75 / 3 is 0.91666..., rounded to the scale of the datatype
+	mean, err := s.Mean()
+	if err != nil || mean.(Decimal).String() != "0.92" {
+		t.Errorf("Mean: got %v, %v", mean, err)
+	}
+
+	// Ties in the mean round half to even
+	ties := mustCreate(t, "d", "decimal(4,1)", []interface{}{mustParse(t, "0.1"), mustParse(t, "0.2")})
+	if mean, err := ties.Mean(); err != nil || mean.(Decimal).String() != "0.2" {
+		t.Errorf("Mean of ties: got %v, %v", mean, err)
+	}
+	negative := mustCreate(t, "d", "decimal(4,1)", []interface{}{mustParse(t, "-0.2"), mustParse(t, "-0.3")})
+	if mean, err := negative.Mean(); err != nil || mean.(Decimal).String() != "-0.2" {
+		t.Errorf("Mean of negative ties: got %v, %v", mean, err)
+	}
+}
@@ -0,0 +1,215 @@
+package series
+
+import (
+	"fmt"
+	"time"
+)
+
+// DatetimeAccessor derives new Series from the datetimes held in a Series.
+// Datetime Series are used as they are, string Series are parsed on demand.
+type DatetimeAccessor struct {
+	s       *Series
+	layouts []string
+}
+
+// Dt returns the datetime accessor of the series. Strings are parsed with the
+// given layouts, or with DefaultDatetimeLayouts when none are given.
+func (s *Series) Dt(layouts ...string) *DatetimeAccessor {
+	return &DatetimeAccessor{s: s, layouts: layouts}
+}
+
+// Year returns the year of each datetime
+func (dt *DatetimeAccessor) Year() (*Series, error) {
+	return dt.field(func(t time.Time) int { return t.Year() })
+}
+
+// Month returns the month of each datetime, from 1 to 12
+func (dt *DatetimeAccessor) Month() (*Series, error) {
+	return dt.field(func(t time.Time) int { return int(t.Month()) })
+}
+
+// Day returns the day of the month of each datetime
+func (dt *DatetimeAccessor) Day() (*Series, error) {
+	return dt.field(func(t time.Time) int { return t.Day() })
+}
+
+// Hour returns the hour of each datetime, from 0 to 23
+func (dt *DatetimeAccessor) Hour() (*Series, error) {
+	return dt.field(func(t time.Time) int { return t.Hour() })
+}
+
+// Minute returns the minute of each datetime
+func (dt *DatetimeAccessor) Minute() (*Series, error) {
+	return dt.field(func(t time.Time) int { return t.Minute() })
+}
+
+// Second returns the second of each datetime
+func (dt *DatetimeAccessor) Second() (*Series, error) {
+	return dt.field(func(t time.Time) int { return t.Second() })
+}
+
+// Weekday returns the day of the week of each datetime, with Sunday as 0
+func (dt *DatetimeAccessor) Weekday() (*Series, error) {
+	return dt.field(func(t time.Time) int { return int(t.Weekday()) })
+}
+
+// DayOfYear returns the day of the year of each datetime, from 1 to 366
+func (dt *DatetimeAccessor) DayOfYear() (*Series, error) {
+	return dt.field(func(t time.Time) int { return t.YearDay() })
+}
+
+// ISOWeek returns the ISO 8601 week number of each datetime, from 1 to 53
+func (dt *DatetimeAccessor) ISOWeek() (*Series, error) {
+	return dt.field(func(t time.Time) int {
+		_, week := t.ISOWeek()
+		return week
+	})
+}
+
+// Quarter returns the quarter of the year of each datetime, from 1 to 4
+func (dt *DatetimeAccessor) Quarter() (*Series, error) {
+	return dt.field(func(t time.Time) int { return (int(t.Month())-1)/3 + 1 })
+}
+
+// Floor rounds each datetime down to a multiple of d since the zero time, as
+// time.Time.Truncate does
+func (dt *DatetimeAccessor) Floor(d time.Duration) (*Series, error) {
+	if d <= 0 {
+		return nil, fmt.Errorf("invalid duration: %s", d)
+	}
+	return dt.datetime(func(t time.Time) time.Time { return t.Truncate(d) })
+}
+
+// Ceil rounds each datetime up to a multiple of d since the zero time
+func (dt *DatetimeAccessor) Ceil(d time.Duration) (*Series, error) {
+	if d <= 0 {
+		return nil, fmt.Errorf("invalid duration: %s", d)
+	}
+	return dt.datetime(func(t time.Time) time.Time {
+		floor := t.Truncate(d)
+		if floor.Equal(t) {
+			return floor
+		}
+		return floor.Add(d)
+	})
+}
+
+// Truncate sets each datetime to the start of its calendar unit in its own
+// location. The unit is one of year, quarter, month, week (starting on
+// Monday), day, hour, minute or second.
+func (dt *DatetimeAccessor) Truncate(unit string) (*Series, error) {
+	var truncate func(t time.Time) time.Time
+	switch unit {
+	case "year":
+		truncate = func(t time.Time) time.Time {
+			return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+		}
+	case "quarter":
+		truncate = func(t time.Time) time.Time {
+			month := time.Month((int(t.Month())-1)/3*3 + 1)
+			return time.Date(t.Year(), month, 1, 0, 0, 0, 0, t.Location())
+		}
+	case "month":
+		truncate = func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		}
+	case "week":
+		truncate = func(t time.Time) time.Time {
+			offset := (int(t.Weekday()) + 6) % 7
+			return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+		}
+	case "day":
+		truncate = func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		}
+	case "hour":
+		truncate = func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+		}
+	case "minute":
+		truncate = func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location())
+		}
+	case "second":
+		truncate = func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, t.Location())
+		}
+	default:
+		return nil, fmt.Errorf("invalid datetime unit: %s", unit)
+	}
+	return dt.datetime(func(t time.Time) time.Time { return truncate(t) })
+}
+
+// Add adds d to each datetime
+func (dt *DatetimeAccessor) Add(d time.Duration) (*Series, error) {
+	return dt.datetime(func(t time.Time) time.Time { return t.Add(d) })
+}
+
+// AddDate adds years, months and days to each datetime, normalising the
+// result as time.Time.AddDate does
+func (dt *DatetimeAccessor) AddDate(years int, months int, days int) (*Series, error) {
+	return dt.datetime(func(t time.Time) time.Time { return t.AddDate(years, months, days) })
+}
+
+// Format formats each datetime with layout into a string Series
+func (dt *DatetimeAccessor) Format(layout string) (*Series, error) {
+	return dt.apply("string", func(t time.Time) interface{} { return t.Format(layout) })
+}
+
+// ConvertTimeZone converts each datetime to the IANA time zone with the given
+// name, keeping the instant it represents
+func (dt *DatetimeAccessor) ConvertTimeZone(zone string) (*Series, error) {
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone: %s", zone)
+	}
+	return dt.datetime(func(t time.Time) time.Time { return t.In(loc) })
+}
+
+// field derives an int Series from each datetime
+func (dt *DatetimeAccessor) field(fn func(t time.Time) int) (*Series, error) {
+	return dt.apply("int", func(t time.Time) interface{} { return fn(t) })
+}
+
+// datetime derives a datetime Series from each datetime
+func (dt *DatetimeAccessor) datetime(fn func(t time.Time) time.Time) (*Series, error) {
+	return dt.apply("datetime", func(t time.Time) interface{} { return fn(t) })
+}
+
+// apply builds a new Series of the given datatype by calling fn on every
+// datetime. Nil values stay nil and each Entry keeps its Index.
+func (dt *DatetimeAccessor) apply(datatype string, fn func(t time.Time) interface{}) (*Series, error) {
+	s := dt.s
+	if s.Datatype != "datetime" && s.Datatype != "string" {
+		return nil, fmt.Errorf("invalid type: expected datetime or string, got %s", s.Datatype)
+	}
+
+	data := make([]Entry, len(s.Data))
+	for i, entry := range s.Data {
+		data[i].Index = entry.Index
+		if entry.Value == nil {
+			continue
+		}
+
+		var t time.Time
+		switch v := entry.Value.(type) {
+		case time.Time:
+			t = v
+		case string:
+			var err error
+			if t, err = ParseDatetime(v, dt.layouts); err != nil {
+				return nil, fmt.Errorf("%s at position %d: %v", s.Name, i, err)
+			}
+		default:
+			return nil, fmt.Errorf("%s at position %d: invalid type: expected datetime, got %T", s.Name, i, entry.Value)
+		}
+
+		data[i].Value = fn(t)
+	}
+
+	return &Series{
+		Name:     s.Name,
+		Datatype: datatype,
+		Data:     data,
+	}, nil
+}
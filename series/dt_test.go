@@ -0,0 +1,140 @@
+package series
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDatetimeFields(t *testing.T) {
+	// Thursday 2024-02-29, in ISO week 9 of the first quarter
+	leap := time.Date(2024, 2, 29, 13, 45, 30, 0, time.UTC)
+	s := mustCreate(t, "t", "datetime", []interface{}{leap, nil})
+	strs := mustCreate(t, "t", "string", []interface{}{"2024-02-29T13:45:30Z", nil})
+
+	tests := []struct {
+		name  string
+		field func(*DatetimeAccessor) (*Series, error)
+		want  int
+	}{
+		{"year", (*DatetimeAccessor).Year, 2024},
+		{"month", (*DatetimeAccessor).Month, 2},
+		{"day", (*DatetimeAccessor).Day, 29},
+		{"hour", (*DatetimeAccessor).Hour, 13},
+		{"minute", (*DatetimeAccessor).Minute, 45},
+		{"second", (*DatetimeAccessor).Second, 30},
+		{"weekday", (*DatetimeAccessor).Weekday, 4},
+		{"day of year", (*DatetimeAccessor).DayOfYear, 60},
+		{"iso week", (*DatetimeAccessor).ISOWeek, 9},
+		{"quarter", (*DatetimeAccessor).Quarter, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, input := range []*Series{s, strs} {
+				got, err := tt.field(input.Dt())
+				if err != nil {
+					t.Fatalf("%s: %v", tt.name, err)
+				}
+				checkSeries(t, got, "int", []interface{}{tt.want, nil})
+			}
+		})
+	}
+}
+
+func TestDatetimeRounding(t *testing.T) {
+	cet := time.FixedZone("CET", 3600)
+	when := time.Date(2024, 5, 15, 10, 37, 12, 500, cet)
+	s := mustCreate(t, "t", "datetime", []interface{}{when})
+
+	tests := []struct {
+		name string
+		fn   func(*DatetimeAccessor) (*Series, error)
+		want time.Time
+	}{
+		{"floor hour", func(dt *DatetimeAccessor) (*Series, error) { return dt.Floor(time.Hour) }, time.Date(2024, 5, 15, 10, 0, 0, 0, cet)},
+		{"ceil 15 minutes", func(dt *DatetimeAccessor) (*Series, error) { return dt.Ceil(15 * time.Minute) }, time.Date(2024, 5, 15, 10, 45, 0, 0, cet)},
+		{"truncate year", func(dt *DatetimeAccessor) (*Series, error) { return dt.Truncate("year") }, time.Date(2024, 1, 1, 0, 0, 0, 0, cet)},
+		{"truncate quarter", func(dt *DatetimeAccessor) (*Series, error) { return dt.Truncate("quarter") }, time.Date(2024, 4, 1, 0, 0, 0, 0, cet)},
+		{"truncate month", func(dt *DatetimeAccessor) (*Series, error) { return dt.Truncate("month") }, time.Date(2024, 5, 1, 0, 0, 0, 0, cet)},
+		{"truncate week", func(dt *DatetimeAccessor) (*Series, error) { return dt.Truncate("week") }, time.Date(2024, 5, 13, 0, 0, 0, 0, cet)},
+		{"truncate day", func(dt *DatetimeAccessor) (*Series, error) { return dt.Truncate("day") }, time.Date(2024, 5, 15, 0, 0, 0, 0, cet)},
+		{"truncate minute", func(dt *DatetimeAccessor) (*Series, error) { return dt.Truncate("minute") }, time.Date(2024, 5, 15, 10, 37, 0, 0, cet)},
+		{"truncate second", func(dt *DatetimeAccessor) (*Series, error) { return dt.Truncate("second") }, time.Date(2024, 5, 15, 10, 37, 12, 0, cet)},
+		{"add", func(dt *DatetimeAccessor) (*Series, error) { return dt.Add(-time.Hour) }, when.Add(-time.Hour)},
+		{"add date", func(dt *DatetimeAccessor) (*Series, error) { return dt.AddDate(0, 1, 20) }, time.Date(2024, 7, 5, 10, 37, 12, 500, cet)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn(s.Dt())
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			if got.Datatype != "datetime" || !got.Data[0].Value.(time.Time).Equal(tt.want) {
+				t.Errorf("got %s %v, want %v", got.Datatype, got.Data[0].Value, tt.want)
+			}
+		})
+	}
+
+	// A time already on the boundary is its own ceiling
+	exact := mustCreate(t, "t", "datetime", []interface{}{time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)})
+	got, _ := exact.Dt().Ceil(time.Hour)
+	checkSeries(t, got, "datetime", entryValues(exact))
+}
+
+func TestDatetimeFormatAndZones(t *testing.T) {
+	s := mustCreate(t, "t", "string", []interface{}{"01/02/2024 15:04", nil})
+	dt := s.Dt("01/02/2006 15:04")
+
+	formatted, err := dt.Format("2006-01-02")
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	checkSeries(t, formatted, "string", []interface{}{"2024-01-02", nil})
+
+	converted, err := dt.ConvertTimeZone("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("ConvertTimeZone: %v", err)
+	}
+	tokyo := converted.Data[0].Value.(time.Time)
+	if tokyo.Hour() != 0 || tokyo.Day() != 3 || !tokyo.Equal(time.Date(2024, 1, 2, 15, 4, 0, 0, time.UTC)) {
+		t.Errorf("got %v", tokyo)
+	}
+}
+
+func TestDatetimeAccessorKeepsIndex(t *testing.T) {
+	s := mustCreate(t, "t", "datetime", []interface{}{time.Unix(0, 0).UTC(), nil})
+	s.Data[0].Index, s.Data[1].Index = 7, 9
+	got, err := s.Dt().Year()
+	if err != nil {
+		t.Fatalf("Year: %v", err)
+	}
+	if got.Name != "t" || got.Data[0].Index != 7 || got.Data[1].Index != 9 {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestDatetimeAccessorErrors(t *testing.T) {
+	s := mustCreate(t, "t", "datetime", []interface{}{time.Now()})
+	tests := []struct {
+		name string
+		fn   func() (*Series, error)
+		want string
+	}{
+		{"wrong datatype", func() (*Series, error) { return mustCreate(t, "n", "int", []interface{}{1}).Dt().Year() }, "expected datetime or string, got int"},
+		{"unparsable string", func() (*Series, error) {
+			return mustCreate(t, "s", "string", []interface{}{"2024-01-01", "soon"}).Dt().Year()
+		}, "s at position 1: cannot parse \"soon\""},
+		{"floor by zero", func() (*Series, error) { return s.Dt().Floor(0) }, "invalid duration"},
+		{"ceil by negative", func() (*Series, error) { return s.Dt().Ceil(-time.Second) }, "invalid duration"},
+		{"unknown unit", func() (*Series, error) { return s.Dt().Truncate("fortnight") }, "invalid datetime unit: fortnight"},
+		{"unknown zone", func() (*Series, error) { return s.Dt().ConvertTimeZone("Mars/Olympus") }, "invalid time zone"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.fn()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package series
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseDuration parses a duration such as "1h30m" or "250ms", as
+// time.ParseDuration does
+func ParseDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse %q as duration", s)
+	}
+	return d, nil
+}
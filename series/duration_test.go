@@ -0,0 +1,160 @@
+package series
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"1h30m", 90 * time.Minute, false},
+		{" 250ms ", 250 * time.Millisecond, false},
+		{"-2s", -2 * time.Second, false},
+		{"0", 0, false},
+		{"90", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDuration(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubDatetimes(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	// The same instant in another zone
+	end := time.Date(2024, 1, 1, 14, 30, 0, 0, time.FixedZone("CET", 3600))
+	starts := mustCreate(t, "start", "datetime", []interface{}{start, nil, start})
+	ends := mustCreate(t, "end", "datetime", []interface{}{end, start, nil})
+
+	latency, err := ends.Sub(starts)
+	if err != nil {
+		t.Fatalf("Sub: %v", err)
+	}
+	checkSeries(t, latency, "duration", []interface{}{90 * time.Minute, nil, nil})
+
+	shifted, err := ends.Sub(latency)
+	if err != nil {
+		t.Fatalf("Sub: %v", err)
+	}
+	if shifted.Datatype != "datetime" || !shifted.Data[0].Value.(time.Time).Equal(start) {
+		t.Errorf("got %s %v", shifted.Datatype, shifted.Data[0].Value)
+	}
+
+	diff, err := latency.Sub(mustCreate(t, "d", "duration", []interface{}{time.Minute, time.Minute, time.Minute}))
+	if err != nil {
+		t.Fatalf("Sub: %v", err)
+	}
+	checkSeries(t, diff, "duration", []interface{}{89 * time.Minute, nil, nil})
+
+	tests := []struct {
+		name  string
+		left  *Series
+		right *Series
+		want  string
+	}{
+		{"duration minus datetime", latency, starts, "cannot subtract datetime from duration"},
+		{"datetime minus int", starts, mustCreate(t, "n", "int", []interface{}{1, 2, 3}), "cannot subtract int from datetime"},
+		{"lengths", starts, mustCreate(t, "d", "datetime", []interface{}{start}), "series length mismatch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.left.Sub(tt.right)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDurationAggregates(t *testing.T) {
+	s := mustCreate(t, "d", "duration", []interface{}{time.Second, nil, 2 * time.Second, 6 * time.Second})
+	tests := []struct {
+		name string
+		fn   func() (interface{}, error)
+		want interface{}
+	}{
+		{"sum", s.Sum, 9 * time.Second},
+		{"mean", s.Mean, 3 * time.Second},
+		{"min", s.Min, time.Second},
+		{"max", s.Max, 6 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn()
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	empty := mustCreate(t, "d", "duration", []interface{}{nil})
+	if sum, err := empty.Sum(); sum != nil || err != nil {
+		t.Errorf("sum of nils: got %v, %v", sum, err)
+	}
+}
+
+func TestDurationMeanExact(t *testing.T) {
+	// Past 2^53 ns a float64 sum would drop the odd nanoseconds
+	long := 200 * 24 * time.Hour
+	tests := []struct {
+		name   string
+		values []interface{}
+		want   time.Duration
+	}{
+		{"large", []interface{}{long + 1, long + 2}, long + 2},
+		{"odd sum", []interface{}{long + 1, long + 4}, long + 2},
+		{"negative", []interface{}{-long - 1, -long - 2}, -long - 2},
+		{"extremes", []interface{}{time.Duration(math.MaxInt64), time.Duration(math.MaxInt64 - 2)}, math.MaxInt64 - 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mustCreate(t, "d", "duration", tt.values).Mean()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", int64(got.(time.Duration)), int64(tt.want))
+			}
+		})
+	}
+}
+
+func TestDurationCompareAndFormat(t *testing.T) {
+	if order, err := Compare(time.Second, time.Minute); err != nil || order != -1 {
+		t.Errorf("Compare: got %d, %v", order, err)
+	}
+	if !Equal(90*time.Second, 90*time.Second) || Equal(time.Second, time.Minute) {
+		t.Error("Equal disagrees with duration values")
+	}
+
+	s := mustCreate(t, "d", "duration", []interface{}{time.Second, time.Hour, nil})
+	mask, err := s.Gt(time.Minute)
+	if err != nil {
+		t.Fatalf("Gt: %v", err)
+	}
+	checkSeries(t, mask, "bool", []interface{}{false, true, nil})
+
+	if got := FormatValue(90 * time.Minute); got != "1h30m0s" {
+		t.Errorf("FormatValue: got %q", got)
+	}
+	if !IsValidType(time.Second, "duration") || IsValidType(int64(1), "duration") {
+		t.Error("IsValidType disagrees with duration values")
+	}
+}
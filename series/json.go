@@ -0,0 +1,279 @@
+package series
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// seriesJSON is the wire format of a Series
+type seriesJSON struct {
+	Name       string            `json:"name"`
+	Datatype   string            `json:"datatype"`
+	Categories []string          `json:"categories,omitempty"` // Dictionary of a category series, in order
+	Data       []json.RawMessage `json:"data"`
+}
+
+// MarshalJSON encodes the series as an object holding its name, datatype and
+// values, along with the dictionary of a category series
+func (s *Series) MarshalJSON() ([]byte, error) {
+	data := make([]json.RawMessage, len(s.Data))
+	for i, entry := range s.Data {
+		raw, err := json.Marshal(entry.Value)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding %s at position %d: %v", s.Name, i, err)
+		}
+		data[i] = raw
+	}
+	var categories []string
+	if s.Datatype == "category" {
+		var err error
+		if categories, err = s.Cat().Categories(); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(seriesJSON{
+		Name:       s.Name,
+		Datatype:   s.Datatype,
+		Categories: categories,
+		Data:       data,
+	})
+}
+
+// UnmarshalJSON decodes a series written by MarshalJSON, converting each value
+// to the declared datatype
+func (s *Series) UnmarshalJSON(b []byte) error {
+	var raw seriesJSON
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if !IsValidDatatype(raw.Datatype) {
+		return fmt.Errorf("invalid type: %s", raw.Datatype)
+	}
+
+	// Categories are written as labels and encoded once every label is read
+	datatype := raw.Datatype
+	if datatype == "category" {
+		datatype = "string"
+	}
+
+	data := make([]Entry, len(raw.Data))
+	for i, msg := range raw.Data {
+		value, err := DecodeJSONValue(msg)
+		if err != nil {
+			return err
+		}
+		value, err = FromJSONValue(value, datatype)
+		if err != nil {
+			return fmt.Errorf("%s at position %d: %v", raw.Name, i, err)
+		}
+		data[i] = Entry{
+			Value: value,
+			Index: i,
+		}
+	}
+
+	decoded := &Series{
+		Name:     raw.Name,
+		Datatype: datatype,
+		Data:     data,
+	}
+	if raw.Datatype == "category" {
+		var err error
+		if decoded, err = decoded.AsCategory(raw.Categories...); err != nil {
+			return err
+		}
+	}
+	*s = *decoded
+	return nil
+}
+
+// DecodeJSONValue decodes a single JSON value, keeping numbers as json.Number
+// so that they can later be converted to either int or float
+func DecodeJSONValue(msg []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(msg))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// FromJSONValue converts a value produced by a json.Decoder using UseNumber
+// into a value of the given datatype. Datetimes are read from strings in any
+// of the DefaultDatetimeLayouts, durations from nanoseconds or duration strings,
+// decimals from numbers or strings without going through a float, lists
+// from arrays and structs from objects. Go ints, uints and floats, as found in
+// structs built from literals, are accepted wherever a number is.
+func FromJSONValue(value interface{}, datatype string) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if n, ok := toNumber(value); ok {
+		switch {
+		case IsNumericDatatype(datatype), IsDecimalDatatype(datatype):
+			return ConvertValue(value, datatype)
+		case datatype == "duration" && n.kind != 'f':
+			ns, err := ConvertValue(value, "int64")
+			if err != nil {
+				return nil, err
+			}
+			return time.Duration(ns.(int64)), nil
+		}
+	}
+	if IsDecimalDatatype(datatype) {
+		var text string
+		switch v := value.(type) {
+		case json.Number:
+			text = v.String()
+		case string:
+			text = v
+		default:
+			return nil, fmt.Errorf("invalid type: expected %s, got %v", datatype, value)
+		}
+		d, err := ParseDecimal(text)
+		if err != nil {
+			return nil, err
+		}
+		return ConvertValue(d, datatype)
+	}
+	if elem, ok := ParseListType(datatype); ok {
+		raw, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid type: expected %s, got %v", datatype, value)
+		}
+		elems := make([]interface{}, len(raw))
+		for i, v := range raw {
+			var err error
+			if elems[i], err = FromJSONValue(v, elem); err != nil {
+				return nil, err
+			}
+		}
+		return NewList(datatype, elems)
+	}
+
+	switch datatype {
+	case "int":
+		if n, ok := value.(json.Number); ok {
+			if v, err := n.Int64(); err == nil {
+				return int(v), nil
+			}
+		}
+	case "float":
+		if n, ok := value.(json.Number); ok {
+			if v, err := n.Float64(); err == nil {
+				return v, nil
+			}
+		}
+	case "int8", "int16", "int32", "int64":
+		if n, ok := value.(json.Number); ok {
+			if v, err := n.Int64(); err == nil {
+				return ConvertValue(v, datatype)
+			}
+		}
+	case "uint8", "uint16", "uint32", "uint64":
+		if n, ok := value.(json.Number); ok {
+			if v, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+				return ConvertValue(v, datatype)
+			}
+		}
+	case "float32":
+		if n, ok := value.(json.Number); ok {
+			if v, err := strconv.ParseFloat(n.String(), 32); err == nil {
+				return float32(v), nil
+			}
+		}
+	case "string", "bool", "struct":
+		if IsValidType(value, datatype) {
+			return value, nil
+		}
+	case "datetime":
+		if s, ok := value.(string); ok {
+			return ParseDatetime(s, nil)
+		}
+	case "duration":
+		// Durations are written as nanoseconds, but text like "1h30m" is accepted too
+		switch v := value.(type) {
+		case json.Number:
+			if n, err := v.Int64(); err == nil {
+				return time.Duration(n), nil
+			}
+		case string:
+			return ParseDuration(v)
+		}
+	default:
+		return nil, fmt.Errorf("invalid type: %s", datatype)
+	}
+	return nil, fmt.Errorf("invalid type: expected %s, got %v", datatype, value)
+}
+
+// InferJSONType returns the datatype that fits every decoded JSON value,
+// preferring int over float when all numbers are whole. Go ints and uints
+// count as whole numbers and Go floats as fractions, as they would once
+// written to JSON. Arrays give a list whose element datatype fits the elements
+// of every array, and objects give a struct.
+func InferJSONType(values []interface{}) (string, error) {
+	datatype := ""
+	var elems []interface{}
+	hasList := false
+	for _, value := range values {
+		var current string
+		switch v := value.(type) {
+		case nil:
+			continue
+		case []interface{}:
+			elems = append(elems, v...)
+			hasList = true
+			continue
+		case json.Number:
+			current = "int"
+			if _, err := v.Int64(); err != nil {
+				current = "float"
+			}
+		case string:
+			current = "string"
+		case bool:
+			current = "bool"
+		case map[string]interface{}:
+			current = "struct"
+		default:
+			n, ok := toNumber(value)
+			if !ok {
+				return "", fmt.Errorf("unsupported json value: %v", value)
+			}
+			current = "int"
+			if n.kind == 'f' || (n.kind == 'u' && n.u > math.MaxInt64) {
+				current = "float"
+			}
+		}
+
+		switch {
+		case datatype == "" || datatype == current:
+			datatype = current
+		case datatype == "int" && current == "float":
+			datatype = "float"
+		case datatype == "float" && current == "int":
+		default:
+			return "", fmt.Errorf("mixed types in json values: %s and %s", datatype, current)
+		}
+	}
+
+	if hasList {
+		if datatype != "" {
+			return "", fmt.Errorf("mixed types in json values: %s and list", datatype)
+		}
+		elem, err := InferJSONType(elems)
+		if err != nil {
+			return "", err
+		}
+		return ListType(elem), nil
+	}
+	if datatype == "" {
+		return "string", nil
+	}
+	return datatype, nil
+}
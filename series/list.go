@@ -0,0 +1,155 @@
+package series
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ListType returns the datatype of lists whose elements have the given
+// datatype, such as "list<int>"
+func ListType(elem string) string {
+	return "list<" + elem + ">"
+}
+
+// ParseListType returns the element datatype of a list datatype. Elements can
+// be of any datatype but category, lists included.
+func ParseListType(datatype string) (string, bool) {
+	elem, ok := strings.CutPrefix(datatype, "list<")
+	if !ok {
+		return "", false
+	}
+	elem, ok = strings.CutSuffix(elem, ">")
+	if !ok || elem == "category" || !IsValidDatatype(elem) {
+		return "", false
+	}
+	return elem, true
+}
+
+// IsListDatatype reports whether the datatype is a valid list datatype
+func IsListDatatype(datatype string) bool {
+	_, ok := ParseListType(datatype)
+	return ok
+}
+
+// goType returns the Go type of the values of a datatype, which is a slice
+// of the element type for lists
+func goType(datatype string) reflect.Type {
+	if elem, ok := ParseListType(datatype); ok {
+		return reflect.SliceOf(goType(elem))
+	}
+	if IsDecimalDatatype(datatype) {
+		return reflect.TypeOf(Decimal{})
+	}
+	switch datatype {
+	case "int":
+		return reflect.TypeOf(int(0))
+	case "float":
+		return reflect.TypeOf(float64(0))
+	case "string":
+		return reflect.TypeOf("")
+	case "bool":
+		return reflect.TypeOf(false)
+	case "datetime":
+		return reflect.TypeOf(time.Time{})
+	case "duration":
+		return reflect.TypeOf(time.Duration(0))
+	case "int8":
+		return reflect.TypeOf(int8(0))
+	case "int16":
+		return reflect.TypeOf(int16(0))
+	case "int32":
+		return reflect.TypeOf(int32(0))
+	case "int64":
+		return reflect.TypeOf(int64(0))
+	case "uint8":
+		return reflect.TypeOf(uint8(0))
+	case "uint16":
+		return reflect.TypeOf(uint16(0))
+	case "uint32":
+		return reflect.TypeOf(uint32(0))
+	case "uint64":
+		return reflect.TypeOf(uint64(0))
+	case "float32":
+		return reflect.TypeOf(float32(0))
+	case "struct":
+		return reflect.TypeOf(map[string]interface{}{})
+	}
+	return nil
+}
+
+// isValidList reports whether value is a slice of the element type. Decimal
+// and list elements are also checked one by one.
+func isValidList(value interface{}, elem string) bool {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem() != goType(elem) {
+		return false
+	}
+	if IsDecimalDatatype(elem) || IsListDatatype(elem) {
+		for i := 0; i < rv.Len(); i++ {
+			if !IsValidType(rv.Index(i).Interface(), elem) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// NewList builds a value of a list datatype, a typed slice such as []int for
+// "list<int>", from its elements. Lists cannot hold nil elements.
+func NewList(datatype string, elems []interface{}) (interface{}, error) {
+	elem, ok := ParseListType(datatype)
+	if !ok {
+		return nil, fmt.Errorf("invalid list type: %s", datatype)
+	}
+	list := reflect.MakeSlice(goType(datatype), len(elems), len(elems))
+	for i, v := range elems {
+		if v == nil || !IsValidType(v, elem) {
+			return nil, fmt.Errorf("invalid list element at position %d: expected %s, got %T", i, elem, v)
+		}
+		list.Index(i).Set(reflect.ValueOf(v))
+	}
+	return list.Interface(), nil
+}
+
+// ListElements returns the elements of a list value, or false when the value
+// is not a slice. A nil value has no elements.
+func ListElements(value interface{}) ([]interface{}, bool) {
+	if value == nil {
+		return nil, true
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice {
+		return nil, false
+	}
+	elems := make([]interface{}, rv.Len())
+	for i := range elems {
+		elems[i] = rv.Index(i).Interface()
+	}
+	return elems, true
+}
+
+// isList reports whether a value is a slice, which cannot be compared with ==
+func isList(value interface{}) bool {
+	return value != nil && reflect.TypeOf(value).Kind() == reflect.Slice
+}
+
+// equalLists reports whether two lists have the same length and equal
+// elements
+func equalLists(a interface{}, b interface{}) bool {
+	if !isList(a) || !isList(b) {
+		return false
+	}
+	ea, _ := ListElements(a)
+	eb, _ := ListElements(b)
+	if len(ea) != len(eb) {
+		return false
+	}
+	for i := range ea {
+		if !Equal(ea[i], eb[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package series
+
+import "math/bits"
+
+// Bitmap holds one bit per position of a Series, packed 64 to a word. A
+// Series does not keep a bitmap of its own: a nil Value in Data is what marks
+// a null, and every null check in the package reads the values directly.
+// Validity builds a Bitmap for callers that want the nulls in this form.
+type Bitmap struct {
+	words []uint64
+	n     int
+}
+
+// NewBitmap returns a bitmap of n cleared bits
+func NewBitmap(n int) *Bitmap {
+	return &Bitmap{
+		words: make([]uint64, (n+63)/64),
+		n:     n,
+	}
+}
+
+// Len returns the number of bits in the bitmap
+func (b *Bitmap) Len() int {
+	return b.n
+}
+
+// Get reports whether the bit at position i is set
+func (b *Bitmap) Get(i int) bool {
+	return b.words[i/64]&(1<<(i%64)) != 0
+}
+
+// Set sets or clears the bit at position i
+func (b *Bitmap) Set(i int, value bool) {
+	if value {
+		b.words[i/64] |= 1 << (i % 64)
+	} else {
+		b.words[i/64] &^= 1 << (i % 64)
+	}
+}
+
+// Count returns the number of set bits
+func (b *Bitmap) Count() int {
+	count := 0
+	for _, word := range b.words {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
+
+// Validity returns a bitmap with the bit of every non-nil value set. It is a
+// snapshot built by scanning Data on each call, and it does not change when
+// Data does.
+func (s *Series) Validity() *Bitmap {
+	validity := NewBitmap(len(s.Data))
+	for i, entry := range s.Data {
+		if entry.Value != nil {
+			validity.Set(i, true)
+		}
+	}
+	return validity
+}
+
+// NullCount returns the number of nil values in the series, counting them
+// without building a bitmap
+func (s *Series) NullCount() int {
+	count := 0
+	for _, entry := range s.Data {
+		if entry.Value == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// IsNull returns a bool Series that is true where the series holds nil. Each
+// Entry keeps its Index.
+func (s *Series) IsNull() *Series {
+	return s.nullMask(false)
+}
+
+// NotNull returns a bool Series that is true where the series holds a value.
+// Each Entry keeps its Index.
+func (s *Series) NotNull() *Series {
+	return s.nullMask(true)
+}
+
+// nullMask builds a bool Series that is true where holding a value equals
+// valid
+func (s *Series) nullMask(valid bool) *Series {
+	data := make([]Entry, len(s.Data))
+	for i, entry := range s.Data {
+		data[i] = Entry{
+			Value: (entry.Value != nil) == valid,
+			Index: entry.Index,
+		}
+	}
+	return &Series{
+		Name:     s.Name,
+		Datatype: "bool",
+		Data:     data,
+	}
+}
@@ -0,0 +1,107 @@
+package series
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBitmap(t *testing.T) {
+	b := NewBitmap(130)
+	if b.Len() != 130 || b.Count() != 0 {
+		t.Fatalf("got length %d and count %d, want 130 and 0", b.Len(), b.Count())
+	}
+	for _, i := range []int{0, 63, 64, 129} {
+		b.Set(i, true)
+	}
+	b.Set(63, false)
+	for i := 0; i < b.Len(); i++ {
+		want := i == 0 || i == 64 || i == 129
+		if b.Get(i) != want {
+			t.Errorf("bit %d: got %v, want %v", i, b.Get(i), want)
+		}
+	}
+	if b.Count() != 3 {
+		t.Errorf("got count %d, want 3", b.Count())
+	}
+}
+
+func TestValidityAndNullCount(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []interface{}
+		nulls  int
+	}{
+		{"empty", []interface{}{}, 0},
+		{"no nulls", []interface{}{1, 2}, 0},
+		{"some nulls", []interface{}{nil, 1, nil}, 2},
+		{"all nulls", []interface{}{nil, nil}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := mustCreate(t, "s", "int", tt.values)
+			if got := s.NullCount(); got != tt.nulls {
+				t.Errorf("NullCount: got %d, want %d", got, tt.nulls)
+			}
+			validity := s.Validity()
+			if validity.Len() != len(tt.values) || validity.Count() != len(tt.values)-tt.nulls {
+				t.Errorf("Validity: got length %d and count %d", validity.Len(), validity.Count())
+			}
+			for i, v := range tt.values {
+				if validity.Get(i) != (v != nil) {
+					t.Errorf("Validity bit %d: got %v", i, validity.Get(i))
+				}
+			}
+		})
+	}
+}
+
+func TestValiditySnapshot(t *testing.T) {
+	s := mustCreate(t, "s", "int", []interface{}{1, nil})
+	validity := s.Validity()
+	s.Data[1].Value = 2
+	if validity.Get(1) {
+		t.Error("Validity followed a later change to Data")
+	}
+	if !s.Validity().Get(1) || s.NullCount() != 0 {
+		t.Error("a new Validity does not see the change")
+	}
+}
+
+func TestDtypeLeadingNil(t *testing.T) {
+	tests := []struct {
+		datatype string
+		values   []interface{}
+		want     string
+	}{
+		{"int", []interface{}{nil, 1}, "int"},
+		{"string", []interface{}{nil, nil, "a"}, "string"},
+		{"float32", []interface{}{nil, float32(1)}, "float32"},
+		{"int", []interface{}{nil, nil}, "int"},
+		{"int", []interface{}{}, "empty"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.datatype, tt.values), func(t *testing.T) {
+			if got := mustCreate(t, "s", tt.datatype, tt.values).Dtype(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNullCountDoesNotAllocate(t *testing.T) {
+	s := mustCreate(t, "s", "int", []interface{}{1, nil, 3, nil})
+	if allocs := testing.AllocsPerRun(10, func() { s.NullCount() }); allocs != 0 {
+		t.Errorf("NullCount allocated %v times", allocs)
+	}
+}
+
+func TestNullMasks(t *testing.T) {
+	s := mustCreate(t, "s", "string", []interface{}{"a", nil, ""})
+	s.Data[2].Index = 7
+	isNull, notNull := s.IsNull(), s.NotNull()
+	checkSeries(t, isNull, "bool", []interface{}{false, true, false})
+	checkSeries(t, notNull, "bool", []interface{}{true, false, true})
+	if isNull.Data[2].Index != 7 || notNull.Data[2].Index != 7 {
+		t.Error("masks should keep the Index of every Entry")
+	}
+}
@@ -0,0 +1,287 @@
+package series
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// signedBits and unsignedBits give the width of every integer datatype. The
+// "int" datatype is a Go int, so its width depends on the platform.
+var signedBits = map[string]int{
+	"int8":  8,
+	"int16": 16,
+	"int32": 32,
+	"int64": 64,
+	"int":   strconv.IntSize,
+}
+
+var unsignedBits = map[string]int{
+	"uint8":  8,
+	"uint16": 16,
+	"uint32": 32,
+	"uint64": 64,
+}
+
+// IsNumericDatatype reports whether the datatype holds integers or floats
+func IsNumericDatatype(datatype string) bool {
+	_, signed := signedBits[datatype]
+	_, unsigned := unsignedBits[datatype]
+	return signed || unsigned || datatype == "float" || datatype == "float32"
+}
+
+// PromoteType returns the datatype that can hold values of both datatypes.
+// Integers widen to the wider type, mixing signed and unsigned integers gives
+// a signed type wide enough for both, and mixing integers with floats gives a
+// float. A float32 only stays float32 alongside integers of 16 bits or fewer.
+// Mixing uint64 with a signed type gives float, as no integer can hold both.
+// Decimals mixed with decimals or integers give a decimal wide enough for
+// both, see promoteDecimal. A category mixed with a string gives string.
+func PromoteType(a string, b string) (string, bool) {
+	if a == b {
+		return a, true
+	}
+	if (a == "category" && b == "string") || (a == "string" && b == "category") {
+		return "string", true
+	}
+	if IsDecimalDatatype(a) || IsDecimalDatatype(b) {
+		return promoteDecimal(a, b)
+	}
+	if !IsNumericDatatype(a) || !IsNumericDatatype(b) {
+		return "", false
+	}
+
+	// Floats
+	if a == "float" || b == "float" {
+		return "float", true
+	}
+	if a == "float32" || b == "float32" {
+		other := a
+		if a == "float32" {
+			other = b
+		}
+		if signedBits[other] <= 16 && unsignedBits[other] <= 16 {
+			return "float32", true
+		}
+		return "float", true
+	}
+
+	aSigned, aIsSigned := signedBits[a]
+	bSigned, bIsSigned := signedBits[b]
+	switch {
+	case aIsSigned && bIsSigned:
+		// Prefer "int" over "int64" when they are the same width
+		if aSigned > bSigned || (aSigned == bSigned && a == "int") {
+			return a, true
+		}
+		return b, true
+	case !aIsSigned && !bIsSigned:
+		if unsignedBits[a] > unsignedBits[b] {
+			return a, true
+		}
+		return b, true
+	}
+
+	// One signed and one unsigned integer
+	signed, unsigned := a, b
+	if bIsSigned {
+		signed, unsigned = b, a
+	}
+	if signedBits[signed] > unsignedBits[unsigned] {
+		return signed, true
+	}
+	switch unsignedBits[unsigned] {
+	case 8:
+		return "int16", true
+	case 16:
+		return "int32", true
+	case 32:
+		if strconv.IntSize == 64 {
+			return "int", true
+		}
+		return "int64", true
+	}
+	return "float", true
+}
+
+// number is a numeric value widened to 64 bits
+type number struct {
+	kind byte // 'i' for signed, 'u' for unsigned and 'f' for floats
+	i    int64
+	u    uint64
+	f    float64
+}
+
+// toNumber widens a Go integer or float, reporting false for other values
+func toNumber(value interface{}) (number, bool) {
+	switch v := value.(type) {
+	case int:
+		return number{kind: 'i', i: int64(v)}, true
+	case int8:
+		return number{kind: 'i', i: int64(v)}, true
+	case int16:
+		return number{kind: 'i', i: int64(v)}, true
+	case int32:
+		return number{kind: 'i', i: int64(v)}, true
+	case int64:
+		return number{kind: 'i', i: v}, true
+	case uint:
+		return number{kind: 'u', u: uint64(v)}, true
+	case uint8:
+		return number{kind: 'u', u: uint64(v)}, true
+	case uint16:
+		return number{kind: 'u', u: uint64(v)}, true
+	case uint32:
+		return number{kind: 'u', u: uint64(v)}, true
+	case uint64:
+		return number{kind: 'u', u: v}, true
+	case float32:
+		return number{kind: 'f', f: float64(v)}, true
+	case float64:
+		return number{kind: 'f', f: v}, true
+	}
+	return number{}, false
+}
+
+// float returns the number as a float64
+func (n number) float() float64 {
+	switch n.kind {
+	case 'i':
+		return float64(n.i)
+	case 'u':
+		return float64(n.u)
+	}
+	return n.f
+}
+
+// bigInt returns an integer number as a big.Int
+func (n number) bigInt() *big.Int {
+	if n.kind == 'u' {
+		return new(big.Int).SetUint64(n.u)
+	}
+	return big.NewInt(n.i)
+}
+
+// compareNumbers orders two numbers by value, whatever their kinds
+func compareNumbers(a number, b number) int {
+	switch {
+	case a.kind == 'f' || b.kind == 'f':
+		return cmp.Compare(a.float(), b.float())
+	case a.kind == 'i' && b.kind == 'i':
+		return cmp.Compare(a.i, b.i)
+	case a.kind == 'u' && b.kind == 'u':
+		return cmp.Compare(a.u, b.u)
+	case a.kind == 'i':
+		if a.i < 0 {
+			return -1
+		}
+		return cmp.Compare(uint64(a.i), b.u)
+	}
+	if b.i < 0 {
+		return 1
+	}
+	return cmp.Compare(a.u, uint64(b.i))
+}
+
+// ConvertValue converts a value to a numeric datatype when this keeps its
+// value: integers must be in range, and floats must be whole numbers to become
+// integers. Integers always convert to floats, and a float64 in range converts
+// to float32 with rounding. Decimals follow the same rules, and numbers only
+// convert to a decimal datatype when no digits are lost. Nil and values
+// already of the datatype are returned unchanged. A Category converts to the
+// string of its label.
+func ConvertValue(value interface{}, datatype string) (interface{}, error) {
+	if value == nil || IsValidType(value, datatype) {
+		return value, nil
+	}
+	if c, ok := value.(Category); ok && datatype == "string" {
+		return c.Label(), nil
+	}
+	if IsDecimalDatatype(datatype) {
+		d, err := toDecimal(value, datatype)
+		if err != nil {
+			return nil, err
+		}
+		return d, nil
+	}
+	n, ok := toNumber(value)
+	if d, isDecimal := value.(Decimal); isDecimal {
+		n, ok = decimalNumber(d), true
+	}
+	if !ok || !IsNumericDatatype(datatype) {
+		return nil, fmt.Errorf("invalid type: expected %s, got %T", datatype, value)
+	}
+
+	if bits, signed := signedBits[datatype]; signed {
+		var v int64
+		switch n.kind {
+		case 'i':
+			v = n.i
+		case 'u':
+			if n.u > math.MaxInt64 {
+				return nil, fmt.Errorf("value %v overflows %s", value, datatype)
+			}
+			v = int64(n.u)
+		case 'f':
+			if n.f != math.Trunc(n.f) || n.f < math.MinInt64 || n.f >= math.MaxInt64 {
+				return nil, fmt.Errorf("value %v cannot be stored exactly as %s", value, datatype)
+			}
+			v = int64(n.f)
+		}
+		if bits < 64 && (v < -int64(1)<<(bits-1) || v > int64(1)<<(bits-1)-1) {
+			return nil, fmt.Errorf("value %v overflows %s", value, datatype)
+		}
+		switch datatype {
+		case "int8":
+			return int8(v), nil
+		case "int16":
+			return int16(v), nil
+		case "int32":
+			return int32(v), nil
+		case "int64":
+			return v, nil
+		}
+		return int(v), nil
+	}
+
+	if bits, unsigned := unsignedBits[datatype]; unsigned {
+		var v uint64
+		switch n.kind {
+		case 'i':
+			if n.i < 0 {
+				return nil, fmt.Errorf("value %v overflows %s", value, datatype)
+			}
+			v = uint64(n.i)
+		case 'u':
+			v = n.u
+		case 'f':
+			if n.f != math.Trunc(n.f) || n.f < 0 || n.f >= math.MaxUint64 {
+				return nil, fmt.Errorf("value %v cannot be stored exactly as %s", value, datatype)
+			}
+			v = uint64(n.f)
+		}
+		if bits < 64 && v > uint64(1)<<bits-1 {
+			return nil, fmt.Errorf("value %v overflows %s", value, datatype)
+		}
+		switch datatype {
+		case "uint8":
+			return uint8(v), nil
+		case "uint16":
+			return uint16(v), nil
+		case "uint32":
+			return uint32(v), nil
+		}
+		return v, nil
+	}
+
+	f := n.float()
+	if datatype == "float32" {
+		if math.Abs(f) > math.MaxFloat32 && !math.IsInf(f, 0) {
+			return nil, fmt.Errorf("value %v overflows float32", value)
+		}
+		return float32(f), nil
+	}
+	return f, nil
+}
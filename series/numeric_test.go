@@ -0,0 +1,78 @@
+package series
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPromoteType(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+		ok   bool
+	}{
+		{"int8", "int32", "int32", true},
+		{"int64", "int", "int", true},
+		{"uint8", "uint32", "uint32", true},
+		{"int8", "uint8", "int16", true},
+		{"int32", "uint16", "int32", true},
+		{"int", "uint32", "int", true},
+		{"int64", "uint64", "float", true},
+		{"float32", "int16", "float32", true},
+		{"float32", "int32", "float", true},
+		{"float", "uint64", "float", true},
+		{"category", "string", "string", true},
+		{"int", "string", "", false},
+		{"bool", "int", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"+"+tt.b, func(t *testing.T) {
+			for _, pair := range [][2]string{{tt.a, tt.b}, {tt.b, tt.a}} {
+				got, ok := PromoteType(pair[0], pair[1])
+				if got != tt.want || ok != tt.ok {
+					t.Errorf("PromoteType(%s, %s): got %q, %v, want %q, %v", pair[0], pair[1], got, ok, tt.want, tt.ok)
+				}
+			}
+		})
+	}
+}
+
+func TestConvertValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		datatype string
+		want     interface{}
+		wantErr  string
+	}{
+		{"int to int8", 100, "int8", int8(100), ""},
+		{"int8 overflow", 200, "int8", nil, "value 200 overflows int8"},
+		{"negative to uint8", -1, "uint8", nil, "overflows uint8"},
+		{"uint64 to int64", uint64(math.MaxUint64), "int64", nil, "overflows int64"},
+		{"whole float to int", 3.0, "int16", int16(3), ""},
+		{"fraction to int", 3.5, "int", nil, "3.5"},
+		{"float to float32", 0.5, "float32", float32(0.5), ""},
+		{"float32 overflow", math.MaxFloat64, "float32", nil, "overflows float32"},
+		{"uint to float", uint16(7), "float", 7.0, ""},
+		{"nil", nil, "int8", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertValue(tt.value, tt.datatype)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ConvertValue: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
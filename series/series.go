@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"time"
 )
 
 // Entry represents a single value with its index
@@ -78,12 +79,55 @@ func Create(name string, datatype string, values []interface{}) (*Series, error)
 	}, nil
 }
 
+// CreateOptions configures CreateWithOptions
+type CreateOptions struct {
+	Coerce bool // Convert numeric values to the datatype when they fit, see ConvertValue
+}
+
+// CreateWithOptions creates a new Series like Create, but checks every value
+// against the datatype and accepts an empty slice of values. With Coerce set,
+// numeric values of another width are converted instead of being rejected.
+func CreateWithOptions(name string, datatype string, values []interface{}, options CreateOptions) (*Series, error) {
+	if !IsValidDatatype(datatype) {
+		return nil, fmt.Errorf("invalid type: %s", datatype)
+	}
+
+	data := make([]Entry, len(values))
+	for i, v := range values {
+		if options.Coerce {
+			converted, err := ConvertValue(v, datatype)
+			if err != nil {
+				return nil, fmt.Errorf("%s at position %d: %v", name, i, err)
+			}
+			v = converted
+		} else if !IsValidType(v, datatype) {
+			return nil, fmt.Errorf("%s at position %d: invalid type: expected %s, got %T", name, i, datatype, v)
+		}
+		data[i] = Entry{
+			Value: v,
+			Index: i,
+		}
+	}
+
+	return &Series{
+		Name:     name,
+		Datatype: datatype,
+		Data:     data,
+	}, nil
+}
+
 // IsValidType checks if a value matches the expected data type
 func IsValidType(value interface{}, datatype string) bool {
 	// Allow nil values for any type
 	if value == nil {
 		return true
 	}
+	if precision, scale, ok := ParseDecimalType(datatype); ok {
+		return isValidDecimal(value, precision, scale)
+	}
+	if elem, ok := ParseListType(datatype); ok {
+		return isValidList(value, elem)
+	}
 
 	switch datatype {
 	case "int":
@@ -92,17 +136,67 @@ func IsValidType(value interface{}, datatype string) bool {
 	case "float":
 		_, ok := value.(float64)
 		return ok
+	case "int8":
+		_, ok := value.(int8)
+		return ok
+	case "int16":
+		_, ok := value.(int16)
+		return ok
+	case "int32":
+		_, ok := value.(int32)
+		return ok
+	case "int64":
+		_, ok := value.(int64)
+		return ok
+	case "uint8":
+		_, ok := value.(uint8)
+		return ok
+	case "uint16":
+		_, ok := value.(uint16)
+		return ok
+	case "uint32":
+		_, ok := value.(uint32)
+		return ok
+	case "uint64":
+		_, ok := value.(uint64)
+		return ok
+	case "float32":
+		_, ok := value.(float32)
+		return ok
 	case "string":
 		_, ok := value.(string)
 		return ok
 	case "bool":
 		_, ok := value.(bool)
 		return ok
+	case "datetime":
+		_, ok := value.(time.Time)
+		return ok
+	case "duration":
+		_, ok := value.(time.Duration)
+		return ok
+	case "category":
+		_, ok := value.(Category)
+		return ok
+	case "struct":
+		return isStruct(value)
 	default:
 		return false
 	}
 }
 
+// IsValidDatatype checks if the datatype is supported by a Series
+func IsValidDatatype(datatype string) bool {
+	switch datatype {
+	case "int", "float", "string", "bool", "datetime", "duration", "category", "struct":
+		return true
+	case "int8", "int16", "int32", "int64", "uint8", "uint16", "uint32", "uint64", "float32":
+		return true
+	default:
+		return IsDecimalDatatype(datatype) || IsListDatatype(datatype)
+	}
+}
+
 // GetIndex returns the index value at the given position
 func (s *Series) GetIndex(pos int) (int, error) {
 	if pos < 0 || pos >= len(s.Data) {
@@ -147,12 +241,22 @@ func (s *Series) SortByIndex() {
 	})
 }
 
-// Dtype returns the data type of the series
+// Dtype returns the Go type of the values of the series, found through its
+// first non-nil value. A series holding only nil values reports the Go type of
+// its datatype, and an empty series reports "empty".
 func (s *Series) Dtype() string {
 	if len(s.Data) == 0 {
 		return "empty"
 	}
-	return reflect.TypeOf(s.Data[0].Value).String()
+	for _, entry := range s.Data {
+		if entry.Value != nil {
+			return reflect.TypeOf(entry.Value).String()
+		}
+	}
+	if t := goType(s.Datatype); t != nil {
+		return t.String()
+	}
+	return s.Datatype
 }
 
 // Filter keeps only the entries at the specified indexes
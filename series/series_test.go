@@ -0,0 +1,36 @@
+package series
+
+import (
+	"reflect"
+	"testing"
+)
+
+// mustCreate builds a Series, checking every value against the datatype
+func mustCreate(t *testing.T, name string, datatype string, values []interface{}) *Series {
+	t.Helper()
+	s, err := CreateWithOptions(name, datatype, values, CreateOptions{})
+	if err != nil {
+		t.Fatalf("CreateWithOptions: %v", err)
+	}
+	return s
+}
+
+// entryValues returns every value of the series, nil included
+func entryValues(s *Series) []interface{} {
+	values := make([]interface{}, len(s.Data))
+	for i, entry := range s.Data {
+		values[i] = entry.Value
+	}
+	return values
+}
+
+// checkSeries fails the test unless the series has the datatype and values
+func checkSeries(t *testing.T, s *Series, datatype string, values []interface{}) {
+	t.Helper()
+	if s.Datatype != datatype {
+		t.Errorf("%s: datatype %s, want %s", s.Name, s.Datatype, datatype)
+	}
+	if got := entryValues(s); !reflect.DeepEqual(got, values) {
+		t.Errorf("%s: got %v, want %v", s.Name, got, values)
+	}
+}
@@ -0,0 +1,214 @@
+package series
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A struct Series holds nested records as map[string]interface{} values, with
+// fields decoded the way DecodeJSONValue decodes them: nil, bool, string,
+// json.Number, []interface{} and map[string]interface{}.
+
+// isStruct reports whether a value is a struct value, which cannot be
+// compared with ==
+func isStruct(value interface{}) bool {
+	_, ok := value.(map[string]interface{})
+	return ok
+}
+
+// equalStructs reports whether two struct values have the same fields holding
+// equal values
+func equalStructs(a interface{}, b interface{}) bool {
+	ma, ok := a.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	mb, ok := b.(map[string]interface{})
+	if !ok || len(ma) != len(mb) {
+		return false
+	}
+	for key, va := range ma {
+		vb, exists := mb[key]
+		if !exists || !Equal(va, vb) {
+			return false
+		}
+	}
+	return true
+}
+
+// formatJSON returns the JSON text of a struct or list value
+func formatJSON(value interface{}) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(raw)
+}
+
+// pathStep is one step of a JSON path: a field name, or an array position
+// when index is not negative
+type pathStep struct {
+	field string
+	index int
+}
+
+// parsePath splits a JSON path such as "user.address.city" or "items[0].id"
+// into its steps. A leading "$" or "$." is allowed.
+func parsePath(path string) ([]pathStep, error) {
+	rest := strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if rest == "" {
+		return nil, fmt.Errorf("invalid path: %q", path)
+	}
+
+	var steps []pathStep
+	for _, part := range strings.Split(rest, ".") {
+		field, indexes, _ := strings.Cut(part, "[")
+		if field == "" && indexes == "" {
+			return nil, fmt.Errorf("invalid path: %q", path)
+		}
+		if field != "" {
+			steps = append(steps, pathStep{field: field, index: -1})
+		}
+		if indexes == "" {
+			continue
+		}
+		for _, index := range strings.Split(strings.TrimSuffix(indexes, "]"), "][") {
+			n, err := strconv.Atoi(index)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid path: %q", path)
+			}
+			steps = append(steps, pathStep{index: n})
+		}
+	}
+	return steps, nil
+}
+
+// lookupPath follows the steps through a struct value, returning nil when a
+// field or position does not exist
+func lookupPath(value interface{}, steps []pathStep) interface{} {
+	for _, step := range steps {
+		if step.index >= 0 {
+			elems, ok := value.([]interface{})
+			if !ok || step.index >= len(elems) {
+				return nil
+			}
+			value = elems[step.index]
+			continue
+		}
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = fields[step.field]
+	}
+	return value
+}
+
+// StructAccessor reads the fields of a struct Series
+type StructAccessor struct {
+	s *Series
+}
+
+// Struct returns the struct accessor of the series
+func (s *Series) Struct() *StructAccessor {
+	return &StructAccessor{s: s}
+}
+
+// Fields returns the names of the fields found in any value of the series,
+// sorted
+func (sa *StructAccessor) Fields() ([]string, error) {
+	s := sa.s
+	if s.Datatype != "struct" {
+		return nil, fmt.Errorf("invalid type: expected struct, got %s", s.Datatype)
+	}
+
+	seen := make(map[string]bool)
+	var fields []string
+	for i, entry := range s.Data {
+		if entry.Value == nil {
+			continue
+		}
+		record, ok := entry.Value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s at position %d: invalid type: expected struct, got %T", s.Name, i, entry.Value)
+		}
+		for field := range record {
+			if !seen[field] {
+				seen[field] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+	slices.Sort(fields)
+	return fields, nil
+}
+
+// Extract builds a Series named after the path from the value each record
+// holds at a JSON path such as "user.address.city" or "items[0].id". Records
+// missing the path give nil. Values are converted to the datatype as
+// FromJSONValue does, or the datatype is inferred with InferJSONType when it
+// is empty. Each Entry keeps its Index.
+func (sa *StructAccessor) Extract(path string, datatype string) (*Series, error) {
+	steps, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return sa.extract(path, steps, datatype)
+}
+
+// Field builds a Series from one field of each record as Extract does, taking
+// the name as it is, so that it may hold dots or brackets
+func (sa *StructAccessor) Field(name string, datatype string) (*Series, error) {
+	return sa.extract(name, []pathStep{{field: name, index: -1}}, datatype)
+}
+
+// extract builds a Series of the given name from the value each record holds
+// at the end of the steps
+func (sa *StructAccessor) extract(name string, steps []pathStep, datatype string) (*Series, error) {
+	s := sa.s
+	if s.Datatype != "struct" {
+		return nil, fmt.Errorf("invalid type: expected struct, got %s", s.Datatype)
+	}
+	if datatype != "" && (!IsValidDatatype(datatype) || datatype == "category") {
+		return nil, fmt.Errorf("invalid type: %s", datatype)
+	}
+
+	raw := make([]interface{}, len(s.Data))
+	for i, entry := range s.Data {
+		if entry.Value == nil {
+			continue
+		}
+		if !isStruct(entry.Value) {
+			return nil, fmt.Errorf("%s at position %d: invalid type: expected struct, got %T", s.Name, i, entry.Value)
+		}
+		raw[i] = lookupPath(entry.Value, steps)
+	}
+	if datatype == "" {
+		var err error
+		if datatype, err = InferJSONType(raw); err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+	}
+
+	data := make([]Entry, len(s.Data))
+	for i, entry := range s.Data {
+		data[i].Index = entry.Index
+		if IsValidType(raw[i], datatype) {
+			data[i].Value = raw[i]
+			continue
+		}
+		var err error
+		if data[i].Value, err = FromJSONValue(raw[i], datatype); err != nil {
+			return nil, fmt.Errorf("%s at position %d: %v", name, i, err)
+		}
+	}
+
+	return &Series{
+		Name:     name,
+		Datatype: datatype,
+		Data:     data,
+	}, nil
+}
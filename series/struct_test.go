@@ -0,0 +1,124 @@
+package series
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// mustStructs builds a struct series from JSON objects, "null" giving nil
+func mustStructs(t *testing.T, texts ...string) *Series {
+	t.Helper()
+	values := make([]interface{}, len(texts))
+	for i, text := range texts {
+		raw, err := DecodeJSONValue([]byte(text))
+		if err != nil {
+			t.Fatalf("DecodeJSONValue: %v", err)
+		}
+		values[i] = raw
+	}
+	return mustCreate(t, "s", "struct", values)
+}
+
+func TestStructExtract(t *testing.T) {
+	s := mustStructs(t,
+		`{"user":{"name":"ann","tags":["a","b"]},"items":[{"id":1},{"id":2.5}]}`,
+		`{"user":{"name":"bob","tags":[]},"items":[]}`,
+		`null`,
+	)
+	tests := []struct {
+		path     string
+		datatype string
+		want     string
+		values   []interface{}
+	}{
+		{"user.name", "", "string", []interface{}{"ann", "bob", nil}},
+		{"$.user.tags[1]", "", "string", []interface{}{"b", nil, nil}},
+		{"items[0].id", "", "int", []interface{}{1, nil, nil}},
+		{"items[1].id", "", "float", []interface{}{2.5, nil, nil}},
+		{"items[0].id", "float", "float", []interface{}{1.0, nil, nil}},
+		{"user.tags", "", "list<string>", []interface{}{[]string{"a", "b"}, []string{}, nil}},
+		{"missing", "", "string", []interface{}{nil, nil, nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := s.Struct().Extract(tt.path, tt.datatype)
+			if err != nil {
+				t.Fatalf("Extract: %v", err)
+			}
+			if got.Name != tt.path {
+				t.Errorf("got name %s, want %s", got.Name, tt.path)
+			}
+			checkSeries(t, got, tt.want, tt.values)
+		})
+	}
+
+	fields, err := s.Struct().Fields()
+	if err != nil || strings.Join(fields, ",") != "items,user" {
+		t.Errorf("got fields %v, error %v", fields, err)
+	}
+}
+
+func TestStructGoLiterals(t *testing.T) {
+	s := mustCreate(t, "s", "struct", []interface{}{
+		map[string]interface{}{"a": 1, "b": 2.5, "c": uint8(3), "d": "x"},
+		map[string]interface{}{"a": int64(-4), "b": float32(1), "c": uint64(1 << 63)},
+		nil,
+	})
+	tests := []struct {
+		path     string
+		datatype string
+		want     string
+		values   []interface{}
+	}{
+		{"a", "", "int", []interface{}{1, -4, nil}},
+		{"b", "", "float", []interface{}{2.5, 1.0, nil}},
+		{"c", "", "float", []interface{}{3.0, float64(1 << 63), nil}},
+		{"d", "", "string", []interface{}{"x", nil, nil}},
+		{"a", "int64", "int64", []interface{}{int64(1), int64(-4), nil}},
+		{"c", "uint64", "uint64", []interface{}{uint64(3), uint64(1 << 63), nil}},
+		{"a", "decimal(4,1)", "decimal(4,1)", []interface{}{mustParse(t, "1.0"), mustParse(t, "-4.0"), nil}},
+		{"a", "duration", "duration", []interface{}{time.Duration(1), time.Duration(-4), nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path+" "+tt.datatype, func(t *testing.T) {
+			got, err := s.Struct().Extract(tt.path, tt.datatype)
+			if err != nil {
+				t.Fatalf("Extract: %v", err)
+			}
+			checkSeries(t, got, tt.want, tt.values)
+		})
+	}
+
+	// Go numbers are range checked like JSON numbers
+	if _, err := s.Struct().Extract("c", "uint8"); err == nil || !strings.Contains(err.Error(), "overflows uint8") {
+		t.Errorf("got error %v, want overflow", err)
+	}
+}
+
+func TestStructExtractErrors(t *testing.T) {
+	s := mustStructs(t, `{"a":"x","b":[1,"y"]}`)
+	tests := []struct {
+		name     string
+		s        *Series
+		path     string
+		datatype string
+		want     string
+	}{
+		{"empty path", s, "$", "", "invalid path"},
+		{"bad index", s, "a[x]", "", "invalid path"},
+		{"negative index", s, "a[-1]", "", "invalid path"},
+		{"conversion", s, "a", "int", "a at position 0"},
+		{"mixed types", s, "b", "", "mixed types"},
+		{"category", s, "a", "category", "invalid type: category"},
+		{"not a struct", mustCreate(t, "n", "int", []interface{}{1}), "a", "", "expected struct, got int"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.s.Struct().Extract(tt.path, tt.datatype)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}